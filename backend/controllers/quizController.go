@@ -48,7 +48,17 @@ func (qc QuizController) SubmitQuiz(c *gin.Context) {
 		return
 	}
 
-	response, err := qc.qs.EvaluateQuiz(request.SessionID, request.QuestionSubmissions)
+	creditMode := models.CreditModeAllOrNothing
+	if request.CreditMode != "" {
+		var err error
+		creditMode, err = models.ParseCreditMode(request.CreditMode)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid credit mode"})
+			return
+		}
+	}
+
+	response, err := qc.qs.EvaluateQuiz(request.SessionID, request.QuestionSubmissions, creditMode)
 	if err != nil {
 		log.Printf("QuizController: EvaluateQuiz: %v", err.Error())
 		c.JSON(http.StatusBadRequest, gin.H{"message": "Cannot evaluate quiz"})
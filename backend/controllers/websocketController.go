@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/adettinger/go-quizgame/csv"
+	livegame "github.com/adettinger/go-quizgame/liveGame"
 	"github.com/adettinger/go-quizgame/models"
 	"github.com/adettinger/go-quizgame/socket"
 	"github.com/adettinger/go-quizgame/types"
@@ -26,14 +30,18 @@ const PlayerNameMaxLength = 20
 
 // WebSocketController handles WebSocket connections
 type WebSocketController struct {
-	manager  *socket.Manager
-	upgrader websocket.Upgrader
+	rooms         *socket.RoomRegistry
+	questionStore *webserver.QuestionStore
+	archive       *livegame.Archive // Transcripts of finished games
+	upgrader      websocket.Upgrader
 }
 
 // NewWebSocketController creates a new WebSocket controller
 func NewWebSocketController(qs *webserver.QuestionStore) *WebSocketController {
 	return &WebSocketController{
-		manager: socket.NewManager(qs),
+		rooms:         socket.NewRoomRegistry(qs),
+		questionStore: qs,
+		archive:       livegame.NewArchive(""),
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -45,54 +53,31 @@ func NewWebSocketController(qs *webserver.QuestionStore) *WebSocketController {
 	}
 }
 
-func (wsc *WebSocketController) HandleHostConnection(c *gin.Context) {
-	// read and validate game options
-	timeLimitParam := c.Query("timeLimit")
-	questionIdsParam := c.Query("questionIds")
-
-	if timeLimitParam == "" || questionIdsParam == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
-		return
-	}
-	log.Printf("received request params: timeLimit: %v, questionIds", timeLimitParam, questionIdsParam)
-	// Parse fields
-	timeLimit, err := strconv.Atoi(timeLimitParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
-		return
-	}
-	log.Printf("Parsed timeLimit: %d", timeLimit)
+// Sets how chat is filtered in rooms created from now on
+func (wsc *WebSocketController) SetChatFilterOptions(options socket.ChatFilterOptions) {
+	wsc.rooms.SetChatFilterOptions(options)
+}
 
-	questionIdsStrings := strings.Split(questionIdsParam, ",")
-	questionIds := make([]uuid.UUID, len(questionIdsStrings))
-	for index, idString := range questionIdsStrings {
-		id, err := uuid.Parse(idString)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
-			return
-		}
-		questionIds[index] = id
-	}
-	log.Printf("Parsed questionIds: %v", questionIds)
+// Sets where transcripts of games that finish from now on are kept
+func (wsc *WebSocketController) SetArchive(archive *livegame.Archive) {
+	wsc.archive = archive
+}
 
-	// Validate options
-	if timeLimit < QuestionTimeMin || timeLimit > QuestionTimeMax {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+func (wsc *WebSocketController) HandleHostConnection(c *gin.Context) {
+	// A host who lost their connection reclaims their room with the host token
+	if c.Query("token") != "" {
+		wsc.handleHostReconnection(c)
 		return
 	}
-	if len(questionIds) < 1 {
+
+	// read and validate game options
+	options, err := wsc.parseGameOptions(c)
+	if err != nil {
+		log.Printf("Invalid game options: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
 		return
 	}
-	// TODO: Validation can be moved to live game store?
-	for _, id := range questionIds {
-		if !wsc.manager.QuestionStore.ProblemIdExists(id) {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
-			return
-		}
-	}
 	log.Print("Passed param validation")
-	// TODO: Check for duplicate question Ids
 
 	isWebSocketRequest := c.IsWebsocket() ||
 		(c.Request.Header.Get("Connection") == "Upgrade" &&
@@ -106,10 +91,13 @@ func (wsc *WebSocketController) HandleHostConnection(c *gin.Context) {
 		return
 	}
 
-	// Update liveGameStore with options
-	err = wsc.manager.LiveGameStore.SetupGameOptions(timeLimit, questionIds)
+	// Each host gets their own room
+	room := wsc.rooms.CreateRoom()
+	room.LiveGameStore.SetArchive(wsc.archive)
+	err = room.LiveGameStore.SetupGameOptions(options)
 	if err != nil {
-		log.Print("Failed to setup game options: %v", err.Error())
+		log.Printf("Failed to setup game options: %v", err.Error())
+		wsc.rooms.RemoveRoom(room.Code)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to setup game options"})
 		return
 	}
@@ -117,33 +105,257 @@ func (wsc *WebSocketController) HandleHostConnection(c *gin.Context) {
 	conn, err := wsc.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade connection: %v", err)
+		wsc.rooms.RemoveRoom(room.Code)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to upgrade connection"})
 		return
 	}
 
 	// Register as host
 	client := &socket.Client{
-		ID:       wsc.manager.CreateNewClientID(),
+		ID:       room.CreateNewClientID(),
 		Conn:     conn,
-		Manager:  wsc.manager,
+		Manager:  room,
 		Send:     make(chan models.Message, 256),
+		Closing:  make(chan struct{}),
 		UserData: socket.UserData{IsHost: true, Name: "Host", PlayerId: uuid.Nil},
 	}
 	log.Print("New Host Client created")
 
-	wsc.manager.Register <- client
-	client.Logf("New Host Client registered")
+	if !room.RegisterClient(client) {
+		client.ErrorAndKill("Game not found")
+		return
+	}
+	client.Logf("New Host Client registered in room ", room.Code)
 
-	// Start goroutines for reading and writing
-	go wsc.readPump(client)
 	go wsc.writePump(client)
+
+	client.Send <- models.CreateMessage(
+		models.MessageTypeRoomCreated,
+		"System",
+		models.RoomContent{Code: room.Code, HostToken: room.LiveGameStore.GetHostToken()},
+	)
+
+	client.Send <- models.CreateMessage(
+		models.MessageTypePlayerList,
+		"System",
+		models.PlayerListMessageContent{Names: room.LiveGameStore.GetPlayerNameList()},
+	)
+	client.Send <- models.CreateMessage(models.MessageTypeLobbyStatus, "System", room.LiveGameStore.GetLobbyStatus())
+
+	go wsc.readPump(client)
 	client.Logf("Host Read/Write pumps started")
+}
+
+func (wsc *WebSocketController) handleHostReconnection(c *gin.Context) {
+	room, ok := wsc.rooms.GetRoom(c.Query("code"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Game not found"})
+		return
+	}
+	hostToken, err := uuid.Parse(c.Query("token"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid host token"})
+		return
+	}
+	if err := room.LiveGameStore.CheckHostToken(hostToken); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Invalid host token"})
+		return
+	}
+
+	isWebSocketRequest := c.IsWebsocket() ||
+		(c.Request.Header.Get("Connection") == "Upgrade" &&
+			strings.ToLower(c.Request.Header.Get("Upgrade")) == "websocket")
+
+	if !isWebSocketRequest {
+		// This is a regular HTTP request, only check the token
+		c.JSON(http.StatusOK, gin.H{"message": "Host can reconnect"})
+		return
+	}
+
+	conn, err := wsc.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade connection: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to upgrade connection"})
+		return
+	}
 
+	client := &socket.Client{
+		ID:       room.CreateNewClientID(),
+		Conn:     conn,
+		Manager:  room,
+		Send:     make(chan models.Message, 256),
+		Closing:  make(chan struct{}),
+		UserData: socket.UserData{IsHost: true, Name: "Host", PlayerId: uuid.Nil, Reconnected: true},
+	}
+
+	if !room.RegisterClient(client) {
+		client.ErrorAndKill("Game not found")
+		return
+	}
+	client.Logf("Host reconnected to room ", room.Code)
+
+	go wsc.writePump(client)
+
+	client.Send <- models.CreateMessage(
+		models.MessageTypeRoomCreated,
+		"System",
+		models.RoomContent{Code: room.Code, HostToken: hostToken},
+	)
 	client.Send <- models.CreateMessage(
 		models.MessageTypePlayerList,
 		"System",
-		models.PlayerListMessageContent{Names: wsc.manager.LiveGameStore.GetPlayerNameList()},
+		models.PlayerListMessageContent{Names: room.LiveGameStore.GetPlayerNameList()},
 	)
+	client.Send <- models.CreateMessage(models.MessageTypeLobbyStatus, "System", room.LiveGameStore.GetLobbyStatus())
+	client.Send <- models.CreateMessage(
+		models.MessageTypeGameStatus,
+		"System",
+		models.GameStatusContent{Status: string(room.LiveGameStore.GetGameStatus())},
+	)
+	if question, err := room.LiveGameStore.CreateQuestionResponse(); err == nil {
+		client.Send <- models.CreateMessage(models.MessageTypeNextQuestion, "System", question)
+	}
+	if room.LiveGameStore.GetGameStatus() != livegame.GameStatusSetup {
+		if progress, err := room.LiveGameStore.GetProgress(); err == nil {
+			client.Send <- models.CreateMessage(models.MessageTypeProgress, "System", progress)
+		}
+	}
+	if timer, ok := room.LiveGameStore.GetTimer(); ok {
+		client.Send <- models.CreateMessage(models.MessageTypeTimer, "System", timer)
+	}
+
+	go wsc.readPump(client)
+}
+
+// Reads the game options from the query of a host request
+func (wsc *WebSocketController) parseGameOptions(c *gin.Context) (livegame.GameOptions, error) {
+	timeLimitParam := c.Query("timeLimit")
+	questionIdsParam := c.Query("questionIds")
+
+	if timeLimitParam == "" || questionIdsParam == "" {
+		return livegame.GameOptions{}, errors.New("timeLimit and questionIds are required")
+	}
+	log.Printf("received request params: timeLimit: %v, questionIds: %v", timeLimitParam, questionIdsParam)
+	// Parse fields
+	timeLimit, err := strconv.Atoi(timeLimitParam)
+	if err != nil {
+		return livegame.GameOptions{}, fmt.Errorf("Invalid timeLimit: %v", timeLimitParam)
+	}
+	log.Printf("Parsed timeLimit: %d", timeLimit)
+
+	questionIdsStrings := strings.Split(questionIdsParam, ",")
+	questionIds := make([]uuid.UUID, len(questionIdsStrings))
+	for index, idString := range questionIdsStrings {
+		id, err := uuid.Parse(idString)
+		if err != nil {
+			return livegame.GameOptions{}, fmt.Errorf("Invalid questionId: %v", idString)
+		}
+		questionIds[index] = id
+	}
+	log.Printf("Parsed questionIds: %v", questionIds)
+
+	scoringMode := livegame.ScoringModeFlat
+	if scoringParam := c.Query("scoring"); scoringParam != "" {
+		scoringMode, err = livegame.ParseScoringMode(scoringParam)
+		if err != nil {
+			return livegame.GameOptions{}, err
+		}
+	}
+
+	gameMode := livegame.GameModeClassic
+	if modeParam := c.Query("mode"); modeParam != "" {
+		gameMode, err = livegame.ParseGameMode(modeParam)
+		if err != nil {
+			return livegame.GameOptions{}, err
+		}
+	}
+
+	choiceShuffle := livegame.ChoiceShuffleNone
+	if shuffleParam := c.Query("shuffle"); shuffleParam != "" {
+		choiceShuffle, err = livegame.ParseChoiceShuffle(shuffleParam)
+		if err != nil {
+			return livegame.GameOptions{}, err
+		}
+	}
+
+	creditMode := models.CreditModeAllOrNothing
+	if creditModeParam := c.Query("creditMode"); creditModeParam != "" {
+		creditMode, err = models.ParseCreditMode(creditModeParam)
+		if err != nil {
+			return livegame.GameOptions{}, err
+		}
+	}
+
+	maxPlayers := 0
+	if maxPlayersParam := c.Query("maxPlayers"); maxPlayersParam != "" {
+		maxPlayers, err = strconv.Atoi(maxPlayersParam)
+		if err != nil || maxPlayers < 1 {
+			return livegame.GameOptions{}, fmt.Errorf("Invalid maxPlayers: %v", maxPlayersParam)
+		}
+	}
+	lateJoin := livegame.LateJoinPlay
+	if lateJoinParam := c.Query("lateJoin"); lateJoinParam != "" {
+		lateJoin, err = livegame.ParseLateJoinPolicy(lateJoinParam)
+		if err != nil {
+			return livegame.GameOptions{}, err
+		}
+	}
+
+	// A self-paced game lasts as long as every question's time limit unless the host sets a duration
+	duration := 0
+	if durationParam := c.Query("duration"); durationParam != "" {
+		duration, err = strconv.Atoi(durationParam)
+		if err != nil || duration < QuestionTimeMin {
+			return livegame.GameOptions{}, fmt.Errorf("Invalid duration: %v", durationParam)
+		}
+	}
+
+	// Teams are optional. Without them the game is played individually.
+	var teams []string
+	teamScoring := livegame.TeamScoringSum
+	if teamsParam := c.Query("teams"); teamsParam != "" {
+		teams = strings.Split(teamsParam, ",")
+		for _, team := range teams {
+			if !utils.IsPlayerNameValid(team, PlayerNameMaxLength) {
+				return livegame.GameOptions{}, fmt.Errorf("Invalid team name: %v", team)
+			}
+		}
+		if teamScoringParam := c.Query("teamScoring"); teamScoringParam != "" {
+			teamScoring, err = livegame.ParseTeamScoring(teamScoringParam)
+			if err != nil {
+				return livegame.GameOptions{}, err
+			}
+		}
+	}
+
+	// Validate options
+	if timeLimit < QuestionTimeMin || timeLimit > QuestionTimeMax {
+		return livegame.GameOptions{}, fmt.Errorf("timeLimit must be between %d and %d", QuestionTimeMin, QuestionTimeMax)
+	}
+	if len(questionIds) < 1 {
+		return livegame.GameOptions{}, errors.New("At least one question is required")
+	}
+	// TODO: Validation can be moved to live game store?
+	for _, id := range questionIds {
+		if !wsc.questionStore.ProblemIdExists(id) {
+			return livegame.GameOptions{}, fmt.Errorf("Question does not exist: %v", id)
+		}
+	}
+	// TODO: Check for duplicate question Ids
+
+	return livegame.GameOptions{
+		TimeLimit:      timeLimit,
+		QuestionIds:    questionIds,
+		ScoringMode:    scoringMode,
+		Teams:          teams,
+		TeamScoring:    teamScoring,
+		Mode:           gameMode,
+		Duration:       duration,
+		ShuffleChoices: choiceShuffle,
+		CreditMode:     creditMode,
+		MaxPlayers:     maxPlayers,
+		LateJoin:       lateJoin,
+	}, nil
 }
 
 // HandlePlayerConnection handles a new WebSocket connection
@@ -163,74 +375,270 @@ func (wsc *WebSocketController) HandlePlayerConnection(c *gin.Context) {
 		return
 	}
 
-	playerId, err := wsc.manager.LiveGameStore.AddPlayer(playerName)
-	if err != nil {
-		if _, ok := err.(*types.ErrDuplicatePlayerName); ok {
-			c.JSON(http.StatusConflict, gin.H{"message": "Duplicate player name"})
+	room, ok := wsc.rooms.GetRoom(c.Param("code"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Game not found"})
+		return
+	}
+
+	if err := room.LiveGameStore.CheckBanned(playerName, c.ClientIP()); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Player is banned from this game"})
+		return
+	}
+
+	// Players who lost their connection rejoin with the token they were given
+	reconnectToken := uuid.Nil
+	if tokenParam := c.Query("token"); tokenParam != "" {
+		parsedToken, err := uuid.Parse(tokenParam)
+		if err != nil || parsedToken == uuid.Nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid reconnect token"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to add player to game store"})
-		return
+		reconnectToken = parsedToken
 	}
+	isReconnect := reconnectToken != uuid.Nil
 
 	isWebSocketRequest := c.IsWebsocket() ||
 		(c.Request.Header.Get("Connection") == "Upgrade" &&
 			strings.ToLower(c.Request.Header.Get("Upgrade")) == "websocket")
 
-	if !isWebSocketRequest {
-		// This is a regular HTTP request, undo modifications and return success
-		wsc.manager.LiveGameStore.RemovePlayerByName(playerName)
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Player name is valid",
-		})
-		return
+	var playerId uuid.UUID
+	if isReconnect {
+		if !isWebSocketRequest {
+			// This is a regular HTTP request, only check the token
+			if err := room.LiveGameStore.CheckReconnectToken(playerName, reconnectToken); err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"message": "Invalid reconnect token"})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"message": "Player can reconnect"})
+			return
+		}
+		player, err := room.LiveGameStore.ReconnectPlayer(playerName, reconnectToken)
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Invalid reconnect token"})
+			return
+		}
+		playerId = player.Id
+	} else {
+		var err error
+		// In a team game players may pick their team, or are placed in the smallest one
+		playerId, err = room.LiveGameStore.AddPlayerToTeam(playerName, c.Query("team"))
+		if err != nil {
+			if _, ok := err.(*types.ErrDuplicatePlayerName); ok {
+				c.JSON(http.StatusConflict, gin.H{"message": "Duplicate player name"})
+				return
+			}
+			if _, ok := err.(*types.ErrTeamNotFound); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "Team not found"})
+				return
+			}
+			if _, ok := err.(*types.ErrLobbyLocked); ok {
+				c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+				return
+			}
+			if _, ok := err.(*types.ErrLobbyFull); ok {
+				c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+				return
+			}
+			if inProgressErr, ok := err.(*types.ErrGameInProgress); ok {
+				if !inProgressErr.CanSpectate {
+					c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+					return
+				}
+				// The host lets late arrivals watch instead
+				if !isWebSocketRequest {
+					c.JSON(http.StatusOK, gin.H{"message": err.Error(), "spectator": true})
+					return
+				}
+				wsc.HandleSpectatorConnection(c)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to add player to game store"})
+			return
+		}
+
+		if !isWebSocketRequest {
+			// This is a regular HTTP request, undo modifications and return success
+			room.LiveGameStore.RemovePlayerByName(playerName)
+			c.JSON(http.StatusOK, gin.H{
+				"message": "Player name is valid",
+			})
+			return
+		}
 	}
 
 	conn, err := wsc.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade connection: %v", err)
+		if isReconnect {
+			room.LiveGameStore.DisconnectPlayer(playerId)
+		} else {
+			room.LiveGameStore.RemovePlayerByName(playerName)
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to upgrade connection"})
 		return
 	}
 
 	client := &socket.Client{
-		ID:       wsc.manager.CreateNewClientID(),
+		ID:       room.CreateNewClientID(),
 		Conn:     conn,
-		Manager:  wsc.manager,
+		Manager:  room,
 		Send:     make(chan models.Message, 256),
-		UserData: socket.UserData{Name: playerName, PlayerId: playerId},
+		Closing:  make(chan struct{}),
+		UserData: socket.UserData{Name: playerName, PlayerId: playerId, Reconnected: isReconnect, Address: c.ClientIP()},
 	}
 	log.Print("New Client created")
 
-	wsc.manager.Register <- client
-	client.Logf("New Client registered")
+	if !room.RegisterClient(client) {
+		// The room closed while the player was connecting
+		if isReconnect {
+			room.LiveGameStore.DisconnectPlayer(playerId)
+		} else {
+			room.LiveGameStore.RemovePlayerByName(playerName)
+		}
+		client.ErrorAndKill("Game not found")
+		return
+	}
+	client.Logf("New Client registered in room ", room.Code)
+	if isReconnect {
+		room.DisconnectOtherClients(playerId, client.ID)
+	}
 
-	// Start goroutines for reading and writing
-	go wsc.readPump(client)
 	go wsc.writePump(client)
-	client.Logf("Read/Write pumps started")
 
 	// Send welcome message to new client
+	welcomeText := fmt.Sprintf("Welcome, %s!", playerName)
+	if isReconnect {
+		welcomeText = fmt.Sprintf("Welcome back, %s!", playerName)
+	}
 	client.Send <- models.CreateMessage(
 		models.MessageTypeChat,
 		"System",
-		models.MessageTextContent{Text: fmt.Sprintf("Welcome, %s!", playerName)},
+		models.MessageTextContent{Text: welcomeText},
 	)
 
+	player, err := room.LiveGameStore.GetPlayerById(playerId)
+	if err == nil {
+		client.Send <- models.CreateMessage(
+			models.MessageTypeSession,
+			"System",
+			models.SessionContent{PlayerId: player.Id, ReconnectToken: player.ReconnectToken, Team: player.Team},
+		)
+	}
+
+	// Players who join a game that already started need to catch up too
+	status := room.LiveGameStore.GetGameStatus()
+	if isReconnect || status == livegame.GameStatusRunning || status == livegame.GameStatusPaused {
+		state, err := room.LiveGameStore.GetPlayerState(playerId)
+		if err == nil {
+			client.Send <- models.CreateMessage(models.MessageTypePlayerState, "System", state)
+		}
+		if timer, ok := room.LiveGameStore.GetTimer(); ok {
+			client.Send <- models.CreateMessage(models.MessageTypeTimer, "System", timer)
+		}
+	}
+
 	// Send list of players to new client
 	client.Send <- models.CreateMessage(
 		models.MessageTypePlayerList,
 		"System",
-		models.PlayerListMessageContent{Names: wsc.manager.LiveGameStore.GetPlayerNameList()},
+		models.PlayerListMessageContent{Names: room.LiveGameStore.GetPlayerNameList()},
 	)
+	client.Send <- models.CreateMessage(models.MessageTypeLobbyStatus, "System", room.LiveGameStore.GetLobbyStatus())
+
+	go wsc.readPump(client)
+	client.Logf("Read/Write pumps started")
+
+	if !isReconnect {
+		room.LiveGameStore.BroadcastTeamList()
+		room.LiveGameStore.BroadcastLobbyStatus()
+	}
+}
+
+// HandleSpectatorConnection connects a read-only display, such as a projector, to a room
+func (wsc *WebSocketController) HandleSpectatorConnection(c *gin.Context) {
+	room, ok := wsc.rooms.GetRoom(c.Param("code"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Game not found"})
+		return
+	}
+
+	isWebSocketRequest := c.IsWebsocket() ||
+		(c.Request.Header.Get("Connection") == "Upgrade" &&
+			strings.ToLower(c.Request.Header.Get("Upgrade")) == "websocket")
+
+	if !isWebSocketRequest {
+		// This is a regular HTTP request, only check the room exists
+		c.JSON(http.StatusOK, gin.H{"message": "Game found"})
+		return
+	}
+
+	conn, err := wsc.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade connection: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to upgrade connection"})
+		return
+	}
+
+	client := &socket.Client{
+		ID:       room.CreateNewClientID(),
+		Conn:     conn,
+		Manager:  room,
+		Send:     make(chan models.Message, 256),
+		Closing:  make(chan struct{}),
+		UserData: socket.UserData{IsSpectator: true, Name: "Spectator", PlayerId: uuid.Nil, Address: c.ClientIP()},
+	}
+
+	if !room.RegisterClient(client) {
+		client.ErrorAndKill("Game not found")
+		return
+	}
+	client.Logf("New Spectator Client registered in room ", room.Code)
+
+	go wsc.writePump(client)
+
+	// Bring the display up to date with the game so far
+	client.Send <- models.CreateMessage(
+		models.MessageTypeRoomCreated,
+		"System",
+		models.RoomContent{Code: room.Code},
+	)
+	client.Send <- models.CreateMessage(
+		models.MessageTypePlayerList,
+		"System",
+		models.PlayerListMessageContent{Names: room.LiveGameStore.GetPlayerNameList()},
+	)
+	status := room.LiveGameStore.GetGameStatus()
+	client.Send <- models.CreateMessage(
+		models.MessageTypeGameStatus,
+		"System",
+		models.GameStatusContent{Status: string(status)},
+	)
+	if status == livegame.GameStatusRunning || status == livegame.GameStatusPaused || status == livegame.GameStatusDone {
+		client.Send <- models.CreateMessage(models.MessageTypeLeaderboard, "System", room.LiveGameStore.GetLeaderboardContent())
+	}
+	if question, err := room.LiveGameStore.CreateQuestionResponse(); err == nil {
+		client.Send <- models.CreateMessage(models.MessageTypeNextQuestion, "System", question)
+	}
+	if room.LiveGameStore.GetGameStatus() != livegame.GameStatusSetup {
+		if progress, err := room.LiveGameStore.GetProgress(); err == nil {
+			client.Send <- models.CreateMessage(models.MessageTypeProgress, "System", progress)
+		}
+	}
+	if timer, ok := room.LiveGameStore.GetTimer(); ok {
+		client.Send <- models.CreateMessage(models.MessageTypeTimer, "System", timer)
+	}
+
+	go wsc.readPump(client)
 }
 
-// readPump pumps messages from the WebSocket connection to the manager
+// readPump pumps messages from the WebSocket connection to the manager.
+// Start it after queueing a client's initial messages, as Send is closed once it exits.
 func (wsc *WebSocketController) readPump(client *socket.Client) {
 	client.Logf("Starting readPump")
 	defer func() {
 		client.Logf("readPump exiting, unregistering client")
-		wsc.manager.Unregister <- client
+		client.Manager.UnregisterClient(client)
 		client.Conn.Close()
 	}()
 
@@ -273,48 +681,169 @@ func (wsc *WebSocketController) readPump(client *socket.Client) {
 			continue
 		}
 
-		message.PlayerName = client.UserData.Name
+		message.PlayerName = senderName(client)
 		message.Timestamp = time.Now()
 
 		client.Logf("Processing message of type: ", message.Type)
 
+		if client.UserData.IsSpectator {
+			client.Logf("Spectator cannot send messages")
+			client.SendError("Spectators cannot send messages")
+			continue
+		}
+		// Host chat is logged when it is broadcast
+		if client.UserData.IsHost && message.Type != models.MessageTypeChat {
+			client.Manager.LiveGameStore.RecordEvent(models.GameEventHostCommand, message)
+		}
+
 		switch message.Type {
 		case models.MessageTypeChat:
+			if !client.UserData.IsHost && client.Manager.LiveGameStore.IsPlayerMuted(client.UserData.PlayerId) {
+				client.Logf("Muted player cannot chat")
+				client.SendError("You have been muted by the host")
+				break
+			}
+			if !filterChat(client, &message) {
+				break
+			}
 			client.Logf("Broadcasting chat message")
-			wsc.manager.BroadcastMessage(message)
+			client.Manager.BroadcastMessage(message)
+			recordChat(client, message)
+		case models.MessageTypeTeamChat:
+			if client.UserData.IsHost {
+				client.Logf("Host cannot send team chat")
+				break
+			}
+			if client.Manager.LiveGameStore.IsPlayerMuted(client.UserData.PlayerId) {
+				client.Logf("Muted player cannot chat")
+				client.SendError("You have been muted by the host")
+				break
+			}
+			team, teammateIds, err := client.Manager.LiveGameStore.GetTeammateIds(client.UserData.PlayerId)
+			if err != nil {
+				client.Logf("Failed to send team chat", err)
+				client.SendError("Team chat is only available in team games")
+				break
+			}
+			if !filterChat(client, &message) {
+				break
+			}
+			client.Logf("Sending team chat to ", team)
+			message.Team = team
+			for _, id := range teammateIds {
+				client.Manager.SendToPlayer(id, message)
+			}
+			// The host sees every team's chat
+			client.Manager.SendToHost(message)
+			recordChat(client, message)
 		case models.MessageTypeGameUpdate:
 			client.Logf("Broadcasting game update")
-			wsc.manager.BroadcastMessage(message)
+			client.Manager.BroadcastMessage(message)
 		case models.MessageTypeStartGame:
 			if !client.UserData.IsHost {
 				client.Logf("Non host cannot start game")
 				break
 			}
-			err = wsc.manager.LiveGameStore.StartGame()
+			// The store broadcasts the first question once the game is running
+			err = client.Manager.LiveGameStore.StartGame()
 			if err != nil {
-				client.Logf("Failed to start game")
-				client.Send <- models.CreateMessage(
-					models.MessageTypeError,
-					"System",
-					models.MessageTextContent{Text: "Failed to start game"},
-				)
+				client.Logf("Failed to start game", err)
+				client.SendError("Failed to start game")
+			}
+		case models.MessageTypeCloseQuestion:
+			if !client.UserData.IsHost {
+				client.Logf("Non host cannot close question")
+				break
+			}
+			if err := client.Manager.LiveGameStore.CloseQuestion(); err != nil {
+				client.Logf("Failed to close question", err)
+				client.SendError("Failed to close question")
+			}
+		case models.MessageTypeAdvanceQuestion:
+			if !client.UserData.IsHost {
+				client.Logf("Non host cannot advance question")
+				break
+			}
+			if err := client.Manager.LiveGameStore.AdvanceQuestion(); err != nil {
+				client.Logf("Failed to advance question", err)
+				client.SendError("Failed to advance question")
+			}
+		case models.MessageTypePauseGame:
+			if !client.UserData.IsHost {
+				client.Logf("Non host cannot pause game")
+				break
+			}
+			if err := client.Manager.LiveGameStore.PauseGame(); err != nil {
+				client.Logf("Failed to pause game", err)
+				client.SendError("Failed to pause game")
+			}
+		case models.MessageTypeResumeGame:
+			if !client.UserData.IsHost {
+				client.Logf("Non host cannot resume game")
+				break
+			}
+			if err := client.Manager.LiveGameStore.ResumeGame(); err != nil {
+				client.Logf("Failed to resume game", err)
+				client.SendError("Failed to resume game")
+			}
+		case models.MessageTypeSkipQuestion:
+			if !client.UserData.IsHost {
+				client.Logf("Non host cannot skip question")
+				break
+			}
+			if err := client.Manager.LiveGameStore.SkipQuestion(); err != nil {
+				client.Logf("Failed to skip question", err)
+				client.SendError("Failed to skip question")
+			}
+		case models.MessageTypeLockLobby, models.MessageTypeUnlockLobby:
+			if !client.UserData.IsHost {
+				client.Logf("Non host cannot lock the lobby")
+				break
+			}
+			locked := message.Type == models.MessageTypeLockLobby
+			if err := client.Manager.LiveGameStore.SetLobbyLocked(locked); err != nil {
+				client.Logf("Failed to change the lobby", err)
+				client.SendError("Failed to change the lobby")
+			}
+		case models.MessageTypeEndGame:
+			if !client.UserData.IsHost {
+				client.Logf("Non host cannot end game")
+				break
+			}
+			if err := client.Manager.LiveGameStore.EndGame(); err != nil {
+				client.Logf("Failed to end game", err)
+				client.SendError("Failed to end game")
+			}
+		case models.MessageTypeKickPlayer, models.MessageTypeBanPlayer, models.MessageTypeMutePlayer, models.MessageTypeRenamePlayer:
+			if !client.UserData.IsHost {
+				client.Logf("Non host cannot moderate players")
 				break
 			}
-			// Send question data to players
-			msgContent, err := wsc.manager.LiveGameStore.CreateQuestionResponse()
+			if err := handleModeration(client, message); err != nil {
+				client.Logf("Failed to moderate player", err)
+				client.SendError(err.Error())
+			}
+		case models.MessageTypeSubmitAnswer:
+			if client.UserData.IsHost {
+				client.Logf("Host cannot submit answers")
+				break
+			}
+			var submission models.SubmitAnswerContent
+			if err := models.DecodeContent(message.Content, &submission); err != nil {
+				client.Logf("Error parsing answer", err)
+				client.SendError("Invalid answer format")
+				break
+			}
+			_, err := client.Manager.LiveGameStore.SubmitAnswer(client.UserData.PlayerId, submission.QuestionNumber, submission.Answer)
 			if err != nil {
-				client.Logf("Error creating question message: %v", err)
-				client.Send <- models.CreateMessage(
-					models.MessageTypeError,
-					"System",
-					models.MessageTextContent{Text: "Failed to start game"},
-				)
+				client.Logf("Failed to submit answer", err)
+				client.SendError(err.Error())
 				break
 			}
-			wsc.manager.Broadcast <- models.CreateMessage(
-				models.MessageTypeNextQuestion,
+			client.Send <- models.CreateMessage(
+				models.MessageTypeAnswerReceived,
 				"System",
-				msgContent,
+				models.AnswerReceivedContent{QuestionNumber: submission.QuestionNumber},
 			)
 
 		default:
@@ -323,6 +852,112 @@ func (wsc *WebSocketController) readPump(client *socket.Client) {
 	}
 }
 
+// Gets the name to show on a client's messages. Players are looked up since the host may have renamed them.
+func senderName(client *socket.Client) string {
+	if client.UserData.IsHost {
+		return client.UserData.Name
+	}
+	player, err := client.Manager.LiveGameStore.GetPlayerById(client.UserData.PlayerId)
+	if err != nil {
+		return client.UserData.Name
+	}
+	return player.Name
+}
+
+// Runs a chat message through the room's filters, rewriting its text if a filter changed it.
+// Returns false if the message was dropped, after telling the sender why.
+func filterChat(client *socket.Client, message *models.Message) bool {
+	var content models.MessageTextContent
+	if err := models.DecodeContent(message.Content, &content); err != nil {
+		client.Logf("Error parsing chat", err)
+		client.SendError("Invalid chat format")
+		return false
+	}
+	text, err := client.Manager.FilterChat(client, content.Text)
+	if err != nil {
+		client.Logf("Chat dropped", err)
+		client.SendError(err.Error())
+		return false
+	}
+	content.Text = text
+	message.Content = content
+	return true
+}
+
+// Keeps a chat message for the game's transcript
+func recordChat(client *socket.Client, message models.Message) {
+	var content models.MessageTextContent
+	if err := models.DecodeContent(message.Content, &content); err != nil {
+		client.Logf("Failed to record chat", err)
+		return
+	}
+	client.Manager.LiveGameStore.RecordChat(message.PlayerName, content.Text, message.Team)
+}
+
+// Applies a host's kick, ban, mute or rename command and tells the room about it
+func handleModeration(client *socket.Client, message models.Message) error {
+	var command models.ModerationCommandContent
+	if err := models.DecodeContent(message.Content, &command); err != nil {
+		return errors.New("Invalid moderation command")
+	}
+	room := client.Manager
+	notice := models.ModerationContent{PlayerName: command.PlayerName}
+	var removed livegame.LivePlayer
+
+	switch message.Type {
+	case models.MessageTypeKickPlayer:
+		player, err := room.LiveGameStore.KickPlayer(command.PlayerName)
+		if err != nil {
+			return err
+		}
+		removed = player
+		notice.Action = models.ModerationActionKick
+	case models.MessageTypeBanPlayer:
+		player, err := room.LiveGameStore.GetPlayerByName(command.PlayerName)
+		if err != nil {
+			return err
+		}
+		address := ""
+		if command.ByAddress {
+			address, _ = room.PlayerAddress(player.Id)
+		}
+		if _, err := room.LiveGameStore.BanPlayer(command.PlayerName, address); err != nil {
+			return err
+		}
+		removed = player
+		notice.Action = models.ModerationActionBan
+	case models.MessageTypeMutePlayer:
+		if _, err := room.LiveGameStore.SetPlayerMuted(command.PlayerName, command.Muted); err != nil {
+			return err
+		}
+		notice.Action = models.ModerationActionUnmute
+		if command.Muted {
+			notice.Action = models.ModerationActionMute
+		}
+	case models.MessageTypeRenamePlayer:
+		if !utils.IsPlayerNameValid(command.NewName, PlayerNameMaxLength) {
+			return fmt.Errorf("Invalid name: %v", command.NewName)
+		}
+		if _, err := room.LiveGameStore.RenamePlayer(command.PlayerName, command.NewName); err != nil {
+			return err
+		}
+		notice.Action = models.ModerationActionRename
+		notice.NewName = command.NewName
+	}
+
+	client.Logf("Broadcasting moderation: ", notice.Action, " ", notice.PlayerName)
+	room.BroadcastMessage(models.CreateMessage(models.MessageTypeModeration, "System", notice))
+	// Removed players see the notice before their connection is closed
+	if removed.Id != uuid.Nil {
+		room.DisconnectPlayerClients(removed.Id)
+		room.LiveGameStore.BroadcastLobbyStatus()
+	}
+	if notice.Action != models.ModerationActionMute && notice.Action != models.ModerationActionUnmute {
+		room.LiveGameStore.BroadcastTeamList()
+	}
+	return nil
+}
+
 // writePump pumps messages from the manager to the WebSocket connection
 func (wsc *WebSocketController) writePump(client *socket.Client) {
 	client.Logf("Starting writePump")
@@ -345,20 +980,22 @@ func (wsc *WebSocketController) writePump(client *socket.Client) {
 				client.Conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
-
-			client.Logf("Marshaling message of type: ", message.Type)
-			jsonMessage, err := json.Marshal(message)
-			if err != nil {
-				client.Logf("Error marshaling message", err)
-				continue
+			if err := writeMessage(client, message); err != nil {
+				return
 			}
 
-			client.Logf("Writing message: ", string(jsonMessage))
-			if err := client.Conn.WriteMessage(websocket.TextMessage, jsonMessage); err != nil {
-				client.Logf("Error writing message", err)
-				return
+		case <-client.Closing:
+			// The manager dropped the client. Closing the connection ends the readPump,
+			// which unregisters the client, so send what is already queued first.
+			client.Logf("Client dropped, flushing queued messages")
+			client.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			for len(client.Send) > 0 {
+				if err := writeMessage(client, <-client.Send); err != nil {
+					return
+				}
 			}
-			client.Logf("Message written successfully")
+			client.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+			return
 
 		case <-ticker.C:
 			client.Logf("Sending ping")
@@ -371,6 +1008,135 @@ func (wsc *WebSocketController) writePump(client *socket.Client) {
 	}
 }
 
-func (wsc *WebSocketController) GetManager() *socket.Manager {
-	return wsc.manager
+// Writes one message to the client's connection. Messages that cannot be marshaled are skipped.
+func writeMessage(client *socket.Client, message models.Message) error {
+	client.Logf("Marshaling message of type: ", message.Type)
+	jsonMessage, err := json.Marshal(message)
+	if err != nil {
+		client.Logf("Error marshaling message", err)
+		return nil
+	}
+
+	client.Logf("Writing message: ", string(jsonMessage))
+	if err := client.Conn.WriteMessage(websocket.TextMessage, jsonMessage); err != nil {
+		client.Logf("Error writing message", err)
+		return err
+	}
+	client.Logf("Message written successfully")
+	return nil
+}
+
+// Gets the transcript of a finished game as JSON, or as CSV with format=csv.
+// The token query must be the host token of the game.
+func (wsc *WebSocketController) GetResults(c *gin.Context) {
+	gameId, err := uuid.Parse(c.Param("gameId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid game id"})
+		return
+	}
+	transcript, err := wsc.archive.Get(gameId)
+	if err != nil {
+		if _, ok := err.(*types.ErrTranscriptNotFound); ok {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Results not found"})
+			return
+		}
+		log.Printf("Failed to get results: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get results"})
+		return
+	}
+	// Results name every player and what they answered, so only the host may see them
+	if token, err := uuid.Parse(c.Query("token")); err != nil || token == uuid.Nil || token != transcript.HostToken {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Invalid host token"})
+		return
+	}
+
+	switch strings.ToLower(c.DefaultQuery("format", "json")) {
+	case "json":
+		c.JSON(http.StatusOK, transcript)
+	case "csv":
+		var buffer bytes.Buffer
+		if err := csv.WriteResults(&buffer, transcript); err != nil {
+			log.Printf("Failed to write results: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to write results"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=results-%s.csv", gameId))
+		c.Data(http.StatusOK, "text/csv", buffer.Bytes())
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid format"})
+	}
+}
+
+// Streams the event log of a finished game to a spectator connection. The speed query
+// replays it faster than it happened, and commands=true includes what the host sent.
+func (wsc *WebSocketController) HandleReplay(c *gin.Context) {
+	gameId, err := uuid.Parse(c.Param("gameId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid game id"})
+		return
+	}
+	speed := 1.0
+	if speedParam := c.Query("speed"); speedParam != "" {
+		speed, err = strconv.ParseFloat(speedParam, 64)
+		if err != nil || speed < 1 || speed > socket.MaxReplaySpeed {
+			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("speed must be between 1 and %d", socket.MaxReplaySpeed)})
+			return
+		}
+	}
+	includeCommands := c.Query("commands") == "true"
+
+	// Only finished games can be replayed
+	if _, err := wsc.archive.Get(gameId); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Game not found"})
+		return
+	}
+	events, err := wsc.archive.GetEvents(gameId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Game not found"})
+		return
+	}
+
+	isWebSocketRequest := c.IsWebsocket() ||
+		(c.Request.Header.Get("Connection") == "Upgrade" &&
+			strings.ToLower(c.Request.Header.Get("Upgrade")) == "websocket")
+
+	if !isWebSocketRequest {
+		// This is a regular HTTP request, only check the replay exists
+		c.JSON(http.StatusOK, gin.H{"message": "Replay found", "events": len(events)})
+		return
+	}
+
+	conn, err := wsc.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade connection: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to upgrade connection"})
+		return
+	}
+
+	// A replay is not part of any room, so the client is never registered
+	client := &socket.Client{
+		ID:       uuid.New(),
+		Conn:     conn,
+		Send:     make(chan models.Message, 256),
+		Closing:  make(chan struct{}),
+		UserData: socket.UserData{IsSpectator: true, Name: "Spectator", PlayerId: uuid.Nil, Address: c.ClientIP()},
+	}
+	client.Logf("Replaying game ", gameId, " at speed ", speed)
+
+	done := make(chan struct{})
+	go func() {
+		// Spectators do not send anything, so only watch for the connection closing
+		defer close(done)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	go socket.Replay(events, speed, includeCommands, client.Send, done)
+	go wsc.writePump(client)
+}
+
+func (wsc *WebSocketController) GetRooms() *socket.RoomRegistry {
+	return wsc.rooms
 }
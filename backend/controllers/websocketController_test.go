@@ -1,15 +1,20 @@
 package controllers
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	livegame "github.com/adettinger/go-quizgame/liveGame"
 	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/socket"
 	"github.com/adettinger/go-quizgame/webserver"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,16 +28,17 @@ func TestHandleConnectionFull(t *testing.T) {
 	// Create a router with the websocket handler
 	router := gin.New()
 	controller := NewWebSocketController(&webserver.QuestionStore{})
+	room := controller.GetRooms().CreateRoom()
 
 	// Setup the route
-	router.GET("/ws/:playerName", controller.HandlePlayerConnection)
+	router.GET("/ws/:code/:playerName", controller.HandlePlayerConnection)
 
 	// Create a test server
 	server := httptest.NewServer(router)
 	defer server.Close()
 
 	// Convert the HTTP URL to a WebSocket URL
-	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + playerName
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + room.Code + "/" + playerName
 
 	// Connect to the WebSocket server
 	dialer := websocket.Dialer{}
@@ -40,42 +46,692 @@ func TestHandleConnectionFull(t *testing.T) {
 	require.NoError(t, err, "Failed to connect to WebSocket server")
 	defer clientConn.Close()
 
-	time.Sleep(1000 * time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
 
-	manager := controller.GetManager()
-
-	player, err := manager.LiveGameStore.GetPlayerByName(playerName)
+	player, err := room.LiveGameStore.GetPlayerByName(playerName)
 	assert.NoError(t, err)
 
-	// Assert manager called to register client
-	select {
-	case client := <-manager.Register:
+	// Assert manager registered the client
+	assert.Equal(t, 1, room.PlayerClientCount())
+	for _, client := range room.PlayerClients {
 		assert.Equal(t, playerName, client.UserData.Name)
 		assert.Equal(t, player.Id, client.UserData.PlayerId)
-	case <-time.After(100 * time.Millisecond):
-		t.Fatal("Timed out waiting for manager to get registration request")
 	}
 
-	// Wait for the welcome message
-	var welcomeMsg models.Message
-	err = clientConn.ReadJSON(&welcomeMsg)
-	require.NoError(t, err, "Failed to read welcome message")
+	// The join and lobby broadcasts can arrive in between, so collect messages by type
+	received := readMessagesByType(t, clientConn, 6)
 
 	// Verify the welcome message
-	assert.Equal(t, models.MessageTypeChat, welcomeMsg.Type)
+	welcomeMsg, ok := received[models.MessageTypeChat]
+	require.True(t, ok, "Failed to read welcome message")
 	assert.Equal(t, "System", welcomeMsg.PlayerName)
 	textContent, ok := welcomeMsg.Content.(map[string]interface{})
 	require.True(t, ok, "Content should be a map")
 	assert.Equal(t, fmt.Sprintf("Welcome, %s!", playerName), textContent["Text"])
 
-	// Wait for the player list message
-	var playerListMsg models.Message
-	err = clientConn.ReadJSON(&playerListMsg)
-	require.NoError(t, err, "Failed to read player list message")
-
 	// Verify the player list message
-	assert.Equal(t, models.MessageTypePlayerList, playerListMsg.Type)
+	playerListMsg, ok := received[models.MessageTypePlayerList]
+	require.True(t, ok, "Failed to read player list message")
 	assert.Equal(t, "System", playerListMsg.PlayerName)
+
+	// Verify the join broadcast
+	joinMsg, ok := received[models.MessageTypeJoin]
+	require.True(t, ok, "Failed to read join message")
+	assert.Equal(t, playerName, joinMsg.PlayerName)
+
+	// Verify the session message carries the reconnect token
+	sessionMsg, ok := received[models.MessageTypeSession]
+	require.True(t, ok, "Failed to read session message")
+	sessionContent, ok := sessionMsg.Content.(map[string]interface{})
+	require.True(t, ok, "Content should be a map")
+	assert.Equal(t, player.ReconnectToken.String(), sessionContent["reconnectToken"])
+
+	// Verify the lobby status counts the new player
+	lobbyMsg, ok := received[models.MessageTypeLobbyStatus]
+	require.True(t, ok, "Failed to read lobby status message")
+	assert.Equal(t, float64(1), lobbyMsg.Content.(map[string]interface{})["playerCount"])
+}
+
+func TestHandleConnectionReconnect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const playerName = "Alex"
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	room := controller.GetRooms().CreateRoom()
+	router.GET("/ws/:code/:playerName", controller.HandlePlayerConnection)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	playerId, err := room.LiveGameStore.AddPlayer(playerName)
+	require.NoError(t, err)
+	require.NoError(t, room.LiveGameStore.DisconnectPlayer(playerId))
+	player, err := room.LiveGameStore.GetPlayerById(playerId)
+	require.NoError(t, err)
+
+	baseURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + room.Code + "/" + playerName
+	dialer := websocket.Dialer{}
+
+	// A wrong token is refused
+	_, resp, err := dialer.Dial(baseURL+"?token="+uuid.New().String(), nil)
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	clientConn, _, err := dialer.Dial(baseURL+"?token="+player.ReconnectToken.String(), nil)
+	require.NoError(t, err, "Failed to reconnect")
+	defer clientConn.Close()
+
+	// Welcome back, session, player state, player list and lobby status, but no join broadcast
+	received := readMessagesByType(t, clientConn, 5)
+	_, ok := received[models.MessageTypeJoin]
+	assert.False(t, ok, "Reconnect should not announce a join")
+	_, ok = received[models.MessageTypePlayerState]
+	assert.True(t, ok, "Failed to read player state message")
+
+	player, err = room.LiveGameStore.GetPlayerByName(playerName)
+	require.NoError(t, err)
+	assert.Equal(t, playerId, player.Id)
+	assert.True(t, player.Connected)
+
+	// Reconnecting from a second tab replaces the first, which may still send before it notices
+	newConn, _, err := dialer.Dial(baseURL+"?token="+player.ReconnectToken.String(), nil)
+	require.NoError(t, err, "Failed to reconnect from a second tab")
+	defer newConn.Close()
+	readMessagesByType(t, newConn, 5)
+	// An answer while no question is open gets an error sent back on the replaced connection
+	clientConn.WriteJSON(models.CreateMessage(models.MessageTypeSubmitAnswer, playerName, models.SubmitAnswerContent{Answer: "late"}))
+
+	// The server closes the replaced connection and keeps serving the new one
+	clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		if _, _, err := clientConn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	require.NoError(t, newConn.WriteJSON(models.CreateMessage(models.MessageTypeChat, playerName, models.MessageTextContent{Text: "hi"})))
+	newConn.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		var msg models.Message
+		require.NoError(t, newConn.ReadJSON(&msg))
+		if msg.Type == models.MessageTypeChat && msg.Content.(map[string]interface{})["Text"] == "hi" {
+			break
+		}
+	}
+}
+
+func TestHandleConnectionLobbyRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	router.GET("/ws/:code/:playerName", controller.HandlePlayerConnection)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+	dialer := websocket.Dialer{}
+
+	createRoom := func(options livegame.GameOptions) (*socket.Manager, string) {
+		room := controller.GetRooms().CreateRoom()
+		options.TimeLimit = 30
+		options.QuestionIds = []uuid.UUID{uuid.New()}
+		require.NoError(t, room.LiveGameStore.SetupGameOptions(options))
+		_, err := room.LiveGameStore.AddPlayer("Alex")
+		require.NoError(t, err)
+		return room, "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + room.Code + "/Bob"
+	}
+
+	t.Run("Locked lobby", func(t *testing.T) {
+		room, url := createRoom(livegame.GameOptions{})
+		require.NoError(t, room.LiveGameStore.SetLobbyLocked(true))
+		_, resp, err := dialer.Dial(url, nil)
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Full lobby", func(t *testing.T) {
+		_, url := createRoom(livegame.GameOptions{MaxPlayers: 1})
+		_, resp, err := dialer.Dial(url, nil)
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Late joins rejected", func(t *testing.T) {
+		room, url := createRoom(livegame.GameOptions{LateJoin: livegame.LateJoinReject})
+		require.NoError(t, room.LiveGameStore.StartGame())
+		_, resp, err := dialer.Dial(url, nil)
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Late joins spectate", func(t *testing.T) {
+		room, url := createRoom(livegame.GameOptions{LateJoin: livegame.LateJoinSpectate})
+		require.NoError(t, room.LiveGameStore.StartGame())
+
+		resp, err := http.Get("http" + strings.TrimPrefix(url, "ws"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		conn, _, err := dialer.Dial(url, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		var msg models.Message
+		require.NoError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, models.MessageTypeRoomCreated, msg.Type)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, 1, room.SpectatorClientCount())
+		assert.False(t, room.LiveGameStore.PlayerExistsByName("Bob"))
+	})
+}
+
+func TestGetResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	archive := livegame.NewArchive("")
+	controller.SetArchive(archive)
+	router.GET("/liveGame/:code/player/:playerName", controller.HandlePlayerConnection)
+	router.GET("/liveGame/results/:gameId", controller.GetResults)
+
+	playerId, otherId, thirdId, kickedId := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	transcript := models.Transcript{
+		GameId:    uuid.New(),
+		HostToken: uuid.New(),
+		Players: []models.TranscriptPlayer{
+			{Id: playerId, Name: "Alex", Team: "Red", Score: 1, CorrectCount: 1},
+			{Id: otherId, Name: "@Bob", Team: "Red"},
+			{Id: thirdId, Name: "Cam", Team: "Red"},
+			{Id: kickedId, Name: "Dee", Team: "Red", Score: 1, CorrectCount: 1, Departure: models.PlayerDepartureKicked},
+		},
+		Questions: []models.TranscriptQuestion{{
+			QuestionNumber: 0,
+			Question:       "1+2",
+			Answers: []models.TranscriptAnswer{
+				{PlayerId: playerId, PlayerName: "Alex", Answer: "3", Correct: true},
+				{PlayerId: otherId, PlayerName: "@Bob", Answer: "=1+2"},
+				{PlayerId: thirdId, PlayerName: "Cam", Answer: "-3"},
+				{PlayerId: kickedId, PlayerName: "Dee", Answer: "3", Correct: true},
+			},
+		}},
+		Standings: []models.LeaderboardEntry{
+			{Name: "Alex", Score: 1, CorrectCount: 1, Rank: 1},
+			{Name: "@Bob", Rank: 2},
+			{Name: "Cam", Rank: 2},
+		},
+	}
+	require.NoError(t, archive.Save(transcript))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	results := "/liveGame/results/" + transcript.GameId.String() + "?token=" + transcript.HostToken.String()
+	recorder := get(results)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got models.Transcript
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, transcript.GameId, got.GameId)
+	assert.Equal(t, "3", got.Questions[0].Answers[0].Answer)
+	assert.NotContains(t, recorder.Body.String(), transcript.HostToken.String())
+
+	// Only the host may see the results
+	assert.Equal(t, http.StatusForbidden, get("/liveGame/results/"+transcript.GameId.String()).Code)
+	assert.Equal(t, http.StatusForbidden, get("/liveGame/results/"+transcript.GameId.String()+"?token="+uuid.New().String()).Code)
+
+	recorder = get(results + "&format=csv")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+	// Cells that a spreadsheet would read as a formula are escaped, and players who left come last
+	assert.Equal(t, "Rank,Name,Team,Score,Correct,Status,Q1,Q1 Correct\n1,Alex,Red,1,1,,3,true\n2,'@Bob,Red,0,0,,'=1+2,false\n2,Cam,Red,0,0,,-3,false\n,Dee,Red,1,1,kicked,3,true\n", recorder.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, get(results+"&format=xml").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/liveGame/results/notanid").Code)
+	assert.Equal(t, http.StatusNotFound, get("/liveGame/results/"+uuid.New().String()).Code)
+}
+
+func TestHandleReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	archive := livegame.NewArchive("")
+	controller.SetArchive(archive)
+	router.GET("/liveGame/replay/:gameId", controller.HandleReplay)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	gameId := uuid.New()
+	eventLog := archive.NewEventLog(gameId)
+	eventLog.Append(models.GameEventBroadcast, models.CreateMessage(models.MessageTypeJoin, "Alex", nil))
+	eventLog.Append(models.GameEventHostCommand, models.CreateMessage(models.MessageTypeStartGame, "Host", nil))
+	eventLog.Append(models.GameEventBroadcast, models.CreateMessage(models.MessageTypeGameStatus, "System", models.GameStatusContent{Status: "done"}))
+	baseURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/liveGame/replay/"
+	dialer := websocket.Dialer{}
+
+	// Games still in progress have no transcript yet
+	_, resp, err := dialer.Dial(baseURL+gameId.String(), nil)
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.NoError(t, archive.Save(models.Transcript{GameId: gameId}))
+	_, resp, err = dialer.Dial(baseURL+gameId.String()+"?speed=0.5", nil)
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	conn, _, err := dialer.Dial(baseURL+gameId.String()+"?speed=8&commands=true", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	for _, want := range []models.MessageType{models.MessageTypeJoin, models.MessageTypeStartGame, models.MessageTypeGameStatus} {
+		var msg models.Message
+		require.NoError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, want, msg.Type)
+	}
+	// The connection is closed once the replay is done
+	_, _, err = conn.ReadMessage()
+	assert.Error(t, err)
+}
+
+func TestHandleReplayOfPlayedGame(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	problem := models.Problem{Id: uuid.New(), Type: models.ProblemTypeText, Question: "1+2", Answer: "3"}
+	questionStore, err := webserver.NewDataStoreFromData([]models.Problem{problem})
+	require.NoError(t, err)
+	router := gin.New()
+	controller := NewWebSocketController(questionStore)
+	archive := livegame.NewArchive("")
+	controller.SetArchive(archive)
+	router.GET("/liveGame/replay/:gameId", controller.HandleReplay)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	room := controller.GetRooms().CreateRoom()
+	room.LiveGameStore.SetArchive(archive)
+	require.NoError(t, room.LiveGameStore.SetupGameOptions(livegame.GameOptions{TimeLimit: 30, QuestionIds: []uuid.UUID{problem.Id}}))
+	gameId := room.LiveGameStore.GetGameId()
+	playerId, err := room.LiveGameStore.AddPlayer("Alex")
+	require.NoError(t, err)
+	require.NoError(t, room.LiveGameStore.StartGame())
+	_, err = room.LiveGameStore.SubmitAnswer(playerId, 0, "3")
+	require.NoError(t, err)
+	require.NoError(t, room.LiveGameStore.AdvanceQuestion())
+	time.Sleep(50 * time.Millisecond)
+
+	// The replay shows what spectators saw, including what only the host and spectators are sent
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/liveGame/replay/"+gameId.String()+"?speed=64", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	seen := map[models.MessageType]bool{}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg models.Message
+		if err := conn.ReadJSON(&msg); err != nil {
+			break
+		}
+		seen[msg.Type] = true
+	}
+	assert.True(t, seen[models.MessageTypeNextQuestion], "Replay should show the question")
+	assert.True(t, seen[models.MessageTypeQuestionResults], "Replay should show the question results")
+	assert.True(t, seen[models.MessageTypeLeaderboard], "Replay should show the leaderboard")
+}
+
+func TestHandleConnectionUnknownRoom(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	router.GET("/ws/:code/:playerName", controller.HandlePlayerConnection)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/NOROOM/Alex"
+	dialer := websocket.Dialer{}
+	_, resp, err := dialer.Dial(wsURL, nil)
+
+	assert.Error(t, err, "Connection should fail for a room that does not exist")
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandleHostReconnect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	room := controller.GetRooms().CreateRoom()
+	require.NoError(t, room.LiveGameStore.SetupGameOptions(livegame.GameOptions{TimeLimit: 30}))
+	hostToken := room.LiveGameStore.GetHostToken()
+	router.GET("/liveGame/host", controller.HandleHostConnection)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	baseURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/liveGame/host?code=" + room.Code + "&token="
+	dialer := websocket.Dialer{}
+
+	t.Run("Wrong token is refused", func(t *testing.T) {
+		_, resp, err := dialer.Dial(baseURL+uuid.New().String(), nil)
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Unknown room is not found", func(t *testing.T) {
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/liveGame/host?code=NOROOM&token=" + hostToken.String()
+		_, resp, err := dialer.Dial(wsURL, nil)
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Host reclaims the room with their token", func(t *testing.T) {
+		conn, _, err := dialer.Dial(baseURL+hostToken.String(), nil)
+		require.NoError(t, err, "Failed to reconnect host")
+		defer conn.Close()
+
+		received := readMessagesByType(t, conn, 4)
+		roomMsg, ok := received[models.MessageTypeRoomCreated]
+		require.True(t, ok, "Failed to read room message")
+		roomContent, ok := roomMsg.Content.(map[string]interface{})
+		require.True(t, ok, "Content should be a map")
+		assert.Equal(t, room.Code, roomContent["code"])
+		assert.Equal(t, hostToken.String(), roomContent["hostToken"])
+		_, ok = received[models.MessageTypeGameStatus]
+		assert.True(t, ok, "Failed to read game status message")
+		_, ok = received[models.MessageTypeLobbyStatus]
+		assert.True(t, ok, "Failed to read lobby status message")
+	})
+}
+
+func TestHostModeration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies(nil))
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	room := controller.GetRooms().CreateRoom()
+	require.NoError(t, room.LiveGameStore.SetupGameOptions(livegame.GameOptions{TimeLimit: 30}))
+	router.GET("/liveGame/host", controller.HandleHostConnection)
+	router.GET("/liveGame/:code/player/:playerName", controller.HandlePlayerConnection)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	wsBase := "ws" + strings.TrimPrefix(server.URL, "http") + "/liveGame/"
+	dialer := websocket.Dialer{}
+
+	hostConn, _, err := dialer.Dial(wsBase+"host?code="+room.Code+"&token="+room.LiveGameStore.GetHostToken().String(), nil)
+	require.NoError(t, err)
+	defer hostConn.Close()
+	readMessagesByType(t, hostConn, 4)
+
+	playerConn, _, err := dialer.Dial(wsBase+room.Code+"/player/Rude", nil)
+	require.NoError(t, err)
+	defer playerConn.Close()
+	readMessagesByType(t, playerConn, 6)
+
+	sendCommand := func(messageType models.MessageType, command models.ModerationCommandContent) {
+		t.Helper()
+		require.NoError(t, hostConn.WriteJSON(models.CreateMessage(messageType, "", command)))
+	}
+	readUntil := func(conn *websocket.Conn, messageType models.MessageType) models.Message {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		for {
+			var msg models.Message
+			require.NoError(t, conn.ReadJSON(&msg))
+			if msg.Type == messageType {
+				return msg
+			}
+		}
+	}
+
+	t.Run("Rename is broadcast and used for chat", func(t *testing.T) {
+		sendCommand(models.MessageTypeRenamePlayer, models.ModerationCommandContent{PlayerName: "Rude", NewName: "Player 1"})
+		msg := readUntil(playerConn, models.MessageTypeModeration)
+		content := msg.Content.(map[string]interface{})
+		assert.Equal(t, models.ModerationActionRename, content["action"])
+		assert.Equal(t, "Player 1", content["newName"])
+
+		require.NoError(t, playerConn.WriteJSON(models.CreateMessage(models.MessageTypeChat, "Rude", models.MessageTextContent{Text: "hi"})))
+		chat := readUntil(hostConn, models.MessageTypeChat)
+		assert.Equal(t, "Player 1", chat.PlayerName)
+	})
+
+	t.Run("Muted player cannot chat", func(t *testing.T) {
+		sendCommand(models.MessageTypeMutePlayer, models.ModerationCommandContent{PlayerName: "Player 1", Muted: true})
+		msg := readUntil(playerConn, models.MessageTypeModeration)
+		assert.Equal(t, models.ModerationActionMute, msg.Content.(map[string]interface{})["action"])
+
+		require.NoError(t, playerConn.WriteJSON(models.CreateMessage(models.MessageTypeChat, "", models.MessageTextContent{Text: "hi"})))
+		readUntil(playerConn, models.MessageTypeError)
+	})
+
+	t.Run("Player cannot moderate", func(t *testing.T) {
+		require.NoError(t, playerConn.WriteJSON(models.CreateMessage(models.MessageTypeKickPlayer, "", models.ModerationCommandContent{PlayerName: "Player 1"})))
+		time.Sleep(50 * time.Millisecond)
+		assert.True(t, room.LiveGameStore.PlayerExistsByName("Player 1"))
+	})
+
+	t.Run("Banned player is disconnected and cannot rejoin", func(t *testing.T) {
+		sendCommand(models.MessageTypeBanPlayer, models.ModerationCommandContent{PlayerName: "Player 1", ByAddress: true})
+		msg := readUntil(playerConn, models.MessageTypeModeration)
+		assert.Equal(t, models.ModerationActionBan, msg.Content.(map[string]interface{})["action"])
+		// Messages still in flight from the banned player must not crash the server
+		playerConn.WriteJSON(models.CreateMessage(models.MessageTypeChat, "", models.MessageTextContent{Text: "bye"}))
+
+		// The server closes the banned player's connection
+		playerConn.SetReadDeadline(time.Now().Add(time.Second))
+		for {
+			if _, _, err := playerConn.ReadMessage(); err != nil {
+				break
+			}
+		}
+		assert.False(t, room.LiveGameStore.PlayerExistsByName("Player 1"))
+
+		// Banned by address, so any name from the same machine is refused
+		_, resp, err := dialer.Dial(wsBase+room.Code+"/player/Someone", nil)
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		// A forged forwarding header does not get around the ban
+		header := http.Header{"X-Forwarded-For": []string{"203.0.113.7"}}
+		_, resp, err = dialer.Dial(wsBase+room.Code+"/player/Someone", header)
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestHandleSpectatorConnection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	room := controller.GetRooms().CreateRoom()
+	require.NoError(t, room.LiveGameStore.SetupGameOptions(livegame.GameOptions{TimeLimit: 30}))
+	_, err := room.LiveGameStore.AddPlayer("Alex")
+	require.NoError(t, err)
+	router.GET("/liveGame/:code/spectator", controller.HandleSpectatorConnection)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	wsBase := "ws" + strings.TrimPrefix(server.URL, "http") + "/liveGame/"
+	dialer := websocket.Dialer{}
+
+	_, resp, err := dialer.Dial(wsBase+"NOROOM/spectator", nil)
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	conn, _, err := dialer.Dial(wsBase+room.Code+"/spectator", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	received := readMessagesByType(t, conn, 3)
+	listMsg, ok := received[models.MessageTypePlayerList]
+	require.True(t, ok, "Failed to read player list")
+	assert.Equal(t, []interface{}{"Alex"}, listMsg.Content.(map[string]interface{})["Names"])
+	_, ok = received[models.MessageTypeGameStatus]
+	assert.True(t, ok, "Failed to read game status")
+
+	// Spectators are read-only and are not players
+	require.NoError(t, conn.WriteJSON(models.CreateMessage(models.MessageTypeChat, "", models.MessageTextContent{Text: "hi"})))
+	errorMsg := readMessagesByType(t, conn, 1)
+	_, ok = errorMsg[models.MessageTypeError]
+	assert.True(t, ok, "Spectator chat should be refused")
+	assert.Equal(t, []string{"Alex"}, room.LiveGameStore.GetPlayerNameList())
+	assert.Equal(t, 1, room.SpectatorClientCount())
+}
+
+func TestTeamChat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	room := controller.GetRooms().CreateRoom()
+	require.NoError(t, room.LiveGameStore.SetupGameOptions(livegame.GameOptions{TimeLimit: 30, Teams: []string{"Red", "Blue"}}))
+	router.GET("/liveGame/:code/player/:playerName", controller.HandlePlayerConnection)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	wsBase := "ws" + strings.TrimPrefix(server.URL, "http") + "/liveGame/" + room.Code + "/player/"
+	dialer := websocket.Dialer{}
+
+	_, resp, err := dialer.Dial(wsBase+"Alex?team=Green", nil)
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	join := func(name string, team string) *websocket.Conn {
+		t.Helper()
+		conn, _, err := dialer.Dial(wsBase+name+"?team="+team, nil)
+		require.NoError(t, err)
+		received := readMessagesByType(t, conn, 7)
+		session := received[models.MessageTypeSession].Content.(map[string]interface{})
+		assert.Equal(t, team, session["team"])
+		_, ok := received[models.MessageTypeTeamList]
+		assert.True(t, ok, "Failed to read team list")
+		return conn
+	}
+	alex := join("Alex", "Red")
+	defer alex.Close()
+	cam := join("Cam", "Red")
+	defer cam.Close()
+	bob := join("Bob", "Blue")
+	defer bob.Close()
+
+	require.NoError(t, alex.WriteJSON(models.CreateMessage(models.MessageTypeTeamChat, "", models.MessageTextContent{Text: "psst"})))
+
+	// Only the sender's team hears team chat
+	cam.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		var msg models.Message
+		require.NoError(t, cam.ReadJSON(&msg))
+		if msg.Type == models.MessageTypeTeamChat {
+			assert.Equal(t, "Alex", msg.PlayerName)
+			assert.Equal(t, "Red", msg.Team)
+			break
+		}
+	}
+	bob.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	for {
+		var msg models.Message
+		if err := bob.ReadJSON(&msg); err != nil {
+			break
+		}
+		assert.NotEqual(t, models.MessageTypeTeamChat, msg.Type, "Other teams should not hear team chat")
+	}
+}
+
+func TestChatFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	controller := NewWebSocketController(&webserver.QuestionStore{})
+	room := controller.GetRooms().CreateRoom()
+	room.SetChatFilter(socket.NewChatFilterChain(socket.ChatFilterOptions{
+		ProfanityWords:  []string{"darn"},
+		MaxLength:       10,
+		Burst:           2,
+		RefillInterval:  time.Hour,
+		DuplicateWindow: time.Minute,
+	}))
+	require.NoError(t, room.LiveGameStore.SetupGameOptions(livegame.GameOptions{TimeLimit: 30}))
+	router.GET("/liveGame/:code/player/:playerName", controller.HandlePlayerConnection)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	wsBase := "ws" + strings.TrimPrefix(server.URL, "http") + "/liveGame/" + room.Code + "/player/"
+	dialer := websocket.Dialer{}
+	alex, _, err := dialer.Dial(wsBase+"Alex", nil)
+	require.NoError(t, err)
+	defer alex.Close()
+	bob, _, err := dialer.Dial(wsBase+"Bob", nil)
+	require.NoError(t, err)
+	defer bob.Close()
+
+	// Reads up to the next message of the type sent by from, and returns its text
+	readText := func(conn *websocket.Conn, messageType models.MessageType, from string) string {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		for {
+			var msg models.Message
+			require.NoError(t, conn.ReadJSON(&msg))
+			if msg.Type == messageType && msg.PlayerName == from {
+				return msg.Content.(map[string]interface{})["Text"].(string)
+			}
+		}
+	}
+	chat := func(text string) {
+		t.Helper()
+		require.NoError(t, alex.WriteJSON(models.CreateMessage(models.MessageTypeChat, "", models.MessageTextContent{Text: text})))
+	}
+
+	chat("oh darn")
+	assert.Equal(t, "oh ****", readText(bob, models.MessageTypeChat, "Alex"))
+
+	// Too long is refused without using up the sender's allowance
+	chat("this is far too long")
+	assert.Contains(t, readText(alex, models.MessageTypeError, "System"), "at most 10")
+
+	chat("oh darn")
+	assert.Equal(t, "You already sent that message", readText(alex, models.MessageTypeError, "System"))
+
+	chat("hi")
+	assert.Contains(t, readText(alex, models.MessageTypeError, "System"), "too quickly")
+}
+
+// Reads count messages from the connection, keyed by type
+func readMessagesByType(t *testing.T, conn *websocket.Conn, count int) map[models.MessageType]models.Message {
+	t.Helper()
+	received := make(map[models.MessageType]models.Message, count)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for range count {
+		var msg models.Message
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("Failed to read message: %v", err)
+		}
+		received[msg.Type] = msg
+	}
+	return received
 }
 
 func TestHandleConnectionInvalidName(t *testing.T) {
@@ -85,16 +741,17 @@ func TestHandleConnectionInvalidName(t *testing.T) {
 	// Create a router with the websocket handler
 	router := gin.New()
 	controller := NewWebSocketController(&webserver.QuestionStore{})
+	room := controller.GetRooms().CreateRoom()
 
 	// Setup the route
-	router.GET("/ws/:playerName", controller.HandlePlayerConnection)
+	router.GET("/ws/:code/:playerName", controller.HandlePlayerConnection)
 
 	// Create a test server
 	server := httptest.NewServer(router)
 	defer server.Close()
 
 	// Test with an empty name (invalid)
-	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/"
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + room.Code + "/"
 
 	// Connect to the WebSocket server
 	dialer := websocket.Dialer{}
@@ -105,7 +762,7 @@ func TestHandleConnectionInvalidName(t *testing.T) {
 
 	// Test with a name that's too long
 	longName := strings.Repeat("X", PlayerNameMaxLength+1)
-	wsURL = "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + longName
+	wsURL = "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/" + room.Code + "/" + longName
 
 	// Connect to the WebSocket server
 	_, _, err = dialer.Dial(wsURL, nil)
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"strings"
 
 	"github.com/adettinger/go-quizgame/models"
 	"github.com/adettinger/go-quizgame/utils"
@@ -16,6 +17,12 @@ import (
 
 // String Problem: ID, string, question, , answer
 // Choice problem: Id, choice, question, choices[], answer
+// Numeric problem: Id, numeric, question, , number with optional tolerance and unit, e.g. 3.14+-0.01 or 100+-5% cm
+// Multi problem: Id, multi, question, choices[], answers[]
+// Ordering problem: Id, ordering, question, choices[], choices[] in order
+// Matching problem: Id, matching, question, choices[], {"choice":"match"} keeping the case players see the matches in
+// True/false problem: Id, truefalse, question, optional [] or ["True","False"], true/false, yes/no or a localized equivalent
+// Any row may end with a column of aliases[], other accepted answers, which only text problems can have.
 
 // TODO: Error messages should indicate they are from parser
 func ParseProblems(fileName string) ([]models.Problem, error) {
@@ -25,6 +32,8 @@ func ParseProblems(fileName string) ([]models.Problem, error) {
 	}
 	defer file.Close()
 	reader := csv.NewReader(file)
+	// Files written before aliases were added have one column fewer
+	reader.FieldsPerRecord = -1
 
 	expectedFieldCount := reflect.TypeOf(models.Problem{}).NumField()
 	lineCount := 0
@@ -38,8 +47,8 @@ func ParseProblems(fileName string) ([]models.Problem, error) {
 			return nil, fmt.Errorf("Failed to parse problems. %v", err.Error())
 		}
 		lineCount++
-		if len(record) != expectedFieldCount {
-			return nil, fmt.Errorf("Expected %d columns per row. Found %d on line %d", expectedFieldCount, len(record), lineCount)
+		if len(record) != expectedFieldCount && len(record) != expectedFieldCount-1 {
+			return nil, fmt.Errorf("Expected %d or %d columns per row. Found %d on line %d", expectedFieldCount-1, expectedFieldCount, len(record), lineCount)
 		}
 		// Validate fields
 		id, err := uuid.Parse(record[0])
@@ -51,16 +60,38 @@ func ParseProblems(fileName string) ([]models.Problem, error) {
 			return nil, fmt.Errorf("Failed to parse problem type for line %d", lineCount)
 		}
 		answer := utils.CleanInput(record[4])
+		if questionType == models.ProblemTypeMatching {
+			// Players are shown the matches, so they keep their case
+			answer = strings.TrimSpace(record[4])
+		}
 		if answer == "" {
 			return nil, fmt.Errorf("Answer cannot be empty string for line %d", lineCount)
 		}
 		choices, err := deserializeArray(record[3])
+		if questionType == models.ProblemTypeTrueFalse && record[3] == "" {
+			// The choices are implied
+			choices, err = []string{}, nil
+		}
 		if err != nil {
 			return nil, fmt.Errorf("Failed to parse choices for line %d", lineCount)
 		}
 		if err := models.ValidateChoices(questionType, choices, answer); err != nil {
 			return nil, fmt.Errorf("Line %d: %v", lineCount, err.Error())
 		}
+		// Written back the same way whatever form the file used
+		answer = models.CanonicalAnswer(questionType, answer)
+		var aliases []string
+		if len(record) == expectedFieldCount && record[5] != "" {
+			if aliases, err = deserializeArray(record[5]); err != nil {
+				return nil, fmt.Errorf("Failed to parse aliases for line %d", lineCount)
+			}
+			for i := range aliases {
+				aliases[i] = utils.CleanInput(aliases[i])
+			}
+		}
+		if err := models.ValidateAliases(questionType, answer, aliases); err != nil {
+			return nil, fmt.Errorf("Line %d: %v", lineCount, err.Error())
+		}
 
 		problems = append(problems, models.Problem{
 			Id:       id,
@@ -68,6 +99,7 @@ func ParseProblems(fileName string) ([]models.Problem, error) {
 			Question: record[2],
 			Choices:  choices,
 			Answer:   answer,
+			Aliases:  aliases,
 		})
 	}
 	if lineCount == 0 {
@@ -0,0 +1,88 @@
+package csv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/adettinger/go-quizgame/models"
+)
+
+// Writes one row per player of a finished game, in final standing order followed by
+// players who left, with the answer they gave to each question and whether it was correct
+func WriteResults(w io.Writer, transcript models.Transcript) error {
+	header := []string{"Rank", "Name", "Team", "Score", "Correct", "Status"}
+	for _, q := range transcript.Questions {
+		header = append(header, fmt.Sprintf("Q%d", q.QuestionNumber+1), fmt.Sprintf("Q%d Correct", q.QuestionNumber+1))
+	}
+	// Standings only carry names, which are unique among the players still in the game
+	players := make(map[string]models.TranscriptPlayer, len(transcript.Players))
+	for _, p := range transcript.Players {
+		if p.Departure == "" {
+			players[p.Name] = p
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("Failed to write results. %v", err)
+	}
+	for _, standing := range transcript.Standings {
+		if err := writer.Write(resultsRow(transcript, strconv.Itoa(standing.Rank), players[standing.Name])); err != nil {
+			return fmt.Errorf("Failed to write results. %v", err)
+		}
+	}
+	for _, p := range transcript.Players {
+		if p.Departure == "" {
+			continue
+		}
+		if err := writer.Write(resultsRow(transcript, "", p)); err != nil {
+			return fmt.Errorf("Failed to write results. %v", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("Failed to write results. %v", err)
+	}
+	return nil
+}
+
+func resultsRow(transcript models.Transcript, rank string, player models.TranscriptPlayer) []string {
+	row := []string{
+		rank,
+		escapeCell(player.Name),
+		escapeCell(player.Team),
+		strconv.Itoa(player.Score),
+		strconv.Itoa(player.CorrectCount),
+		string(player.Departure),
+	}
+	for _, q := range transcript.Questions {
+		answer, correct := "", ""
+		for _, a := range q.Answers {
+			if a.PlayerId == player.Id {
+				answer, correct = escapeCell(a.Answer), strconv.FormatBool(a.Correct)
+				break
+			}
+		}
+		row = append(row, answer, correct)
+	}
+	return row
+}
+
+// Stops a spreadsheet reading what a player typed as a formula, such as =HYPERLINK(...).
+// Negative numbers are left alone.
+func escapeCell(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '@', '\t':
+		return "'" + s
+	case '-':
+		if len(s) == 1 || s[1] < '0' || s[1] > '9' {
+			return "'" + s
+		}
+	}
+	return s
+}
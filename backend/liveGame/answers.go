@@ -0,0 +1,110 @@
+package livegame
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+type LiveAnswer struct {
+	PlayerId    uuid.UUID
+	PlayerName  string // The player's name when they answered
+	Answer      string
+	Correct     bool
+	Score       float64 // Fraction of full credit earned. Only multi-select, ordering and matching answers can be partly right.
+	SubmittedAt time.Time
+	// Time from the question opening until the answer arrived
+	ResponseTime time.Duration
+}
+
+// Records and grades a player's answer to the current question.
+// Each player may answer each question once. The question closes once every player has answered.
+func (lgs *LiveGameStore) SubmitAnswer(playerId uuid.UUID, questionNumber int, answer string) (LiveAnswer, error) {
+	if lgs.GetGameMode() == GameModeSelfPaced {
+		return lgs.submitSelfPacedAnswer(playerId, questionNumber, answer)
+	}
+	liveAnswer, allAnswered, err := lgs.recordAnswer(playerId, questionNumber, answer)
+	if err != nil {
+		return LiveAnswer{}, err
+	}
+	if allAnswered {
+		lgs.closeQuestion(questionNumber)
+	}
+	return liveAnswer, nil
+}
+
+func (lgs *LiveGameStore) recordAnswer(playerId uuid.UUID, questionNumber int, answer string) (LiveAnswer, bool, error) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+
+	if lgs.paused {
+		return LiveAnswer{}, false, &types.ErrGamePaused{}
+	}
+	// The server's clock decides when time is up, whatever the player's device shows
+	if questionNumber == lgs.currentQuestion && lgs.deadlinePassedLocked(time.Now()) {
+		return LiveAnswer{}, false, &types.ErrAnswerTooLate{QuestionNumber: questionNumber, Deadline: lgs.questionDeadline}
+	}
+	if lgs.gameStatus != GameStatusRunning || lgs.questionStatus != QuestionStatusGathering || questionNumber != lgs.currentQuestion {
+		return LiveAnswer{}, false, &types.ErrQuestionNotOpen{QuestionNumber: questionNumber}
+	}
+	index := lgs.playerIndexLocked(playerId)
+	if index < 0 {
+		return LiveAnswer{}, false, &types.ErrPlayerNotFound{PlayerId: playerId}
+	}
+	if lgs.players[index].Eliminated {
+		return LiveAnswer{}, false, &types.ErrPlayerEliminated{PlayerId: playerId}
+	}
+	if questionNumber < lgs.players[index].JoinedOn {
+		return LiveAnswer{}, false, &types.ErrQuestionNotOpen{QuestionNumber: questionNumber}
+	}
+	if _, exists := lgs.answers[questionNumber][playerId]; exists {
+		return LiveAnswer{}, false, &types.ErrAnswerAlreadySubmitted{PlayerId: playerId, QuestionNumber: questionNumber}
+	}
+	problem, err := lgs.questionStore.GetProblemById(lgs.questionIds[questionNumber])
+	if err != nil {
+		return LiveAnswer{}, false, fmt.Errorf("QuestionId does not exist %v", lgs.questionIds[questionNumber])
+	}
+	if err := models.ValidateAnswerShape(problem.Type, answer); err != nil {
+		return LiveAnswer{}, false, &types.ErrInvalidAnswer{QuestionNumber: questionNumber, Reason: err.Error()}
+	}
+
+	now := time.Now()
+	credit := problem.Grade(answer, lgs.creditMode)
+	liveAnswer := LiveAnswer{
+		PlayerId:     playerId,
+		PlayerName:   lgs.players[index].Name,
+		Answer:       answer,
+		Correct:      credit == 1,
+		Score:        credit,
+		SubmittedAt:  now,
+		ResponseTime: now.Sub(lgs.questionStartedAt),
+	}
+	if lgs.answers[questionNumber] == nil {
+		lgs.answers[questionNumber] = make(map[uuid.UUID]LiveAnswer)
+	}
+	lgs.answers[questionNumber][playerId] = liveAnswer
+	return liveAnswer, lgs.allPlayersAnsweredLocked(), nil
+}
+
+// Gets the answers recorded for a question, in the order they were submitted
+func (lgs *LiveGameStore) GetAnswers(questionNumber int) []LiveAnswer {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.sortedAnswersLocked(questionNumber)
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) sortedAnswersLocked(questionNumber int) []LiveAnswer {
+	answers := make([]LiveAnswer, 0, len(lgs.answers[questionNumber]))
+	for _, a := range lgs.answers[questionNumber] {
+		answers = append(answers, a)
+	}
+	slices.SortFunc(answers, func(a, b LiveAnswer) int {
+		return a.SubmittedAt.Compare(b.SubmittedAt)
+	})
+	return answers
+}
@@ -0,0 +1,160 @@
+package livegame_test
+
+import (
+	"errors"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/adettinger/go-quizgame/webserver"
+	"github.com/google/uuid"
+)
+
+var liveProblems = []models.Problem{
+	{Id: uuid.MustParse("c620af48-3af0-4216-a229-65c539a00202"), Type: models.ProblemTypeText, Question: "1+2", Choices: []string{}, Answer: "3"},
+	{Id: uuid.MustParse("60d1584a-9d09-4e2d-be5c-1150fafa454f"), Type: models.ProblemTypeText, Question: "2*2", Choices: []string{}, Answer: "4"},
+}
+
+// Creates a store with a running game over liveProblems and the given players
+func createRunningGame(t *testing.T, playerNames ...string) (*livegame.LiveGameStore, []uuid.UUID) {
+	t.Helper()
+	return createRunningGameWithNotifier(t, nil, playerNames...)
+}
+
+func createRunningGameWithNotifier(t *testing.T, notifier livegame.Notifier, playerNames ...string) (*livegame.LiveGameStore, []uuid.UUID) {
+	t.Helper()
+	store := setupGameWithNotifier(t, notifier, livegame.GameOptions{TimeLimit: 30}, playerNames...)
+	testutils.AssertNoError(t, store.StartGame())
+
+	playerIds := make([]uuid.UUID, len(playerNames))
+	for i, name := range playerNames {
+		player, err := store.GetPlayerByName(name)
+		testutils.AssertNoError(t, err)
+		playerIds[i] = player.Id
+	}
+	return store, playerIds
+}
+
+// Starts a game with the given problems. It asks all of them in order unless options picks
+// the questions, and the time limit defaults to 30 seconds.
+func createGameWithProblems(t *testing.T, notifier livegame.Notifier, options livegame.GameOptions, problems []models.Problem, playerNames ...string) (*livegame.LiveGameStore, []uuid.UUID) {
+	t.Helper()
+	qs, err := webserver.NewDataStoreFromData(problems)
+	testutils.AssertNoError(t, err)
+	store := livegame.NewLiveGameStore(qs)
+	if notifier != nil {
+		store.SetNotifier(notifier)
+	}
+	if options.TimeLimit == 0 {
+		options.TimeLimit = 30
+	}
+	if options.QuestionIds == nil {
+		options.QuestionIds = make([]uuid.UUID, len(problems))
+		for i, p := range problems {
+			options.QuestionIds[i] = p.Id
+		}
+	}
+	testutils.AssertNoError(t, store.SetupGameOptions(options))
+	ids := make([]uuid.UUID, len(playerNames))
+	for i, name := range playerNames {
+		ids[i], err = store.AddPlayer(name)
+		testutils.AssertNoError(t, err)
+	}
+	testutils.AssertNoError(t, store.StartGame())
+	return store, ids
+}
+
+// Creates a store that is set up with the given players, but not started.
+// Questions default to liveProblems.
+func setupGameWithNotifier(t *testing.T, notifier livegame.Notifier, options livegame.GameOptions, playerNames ...string) *livegame.LiveGameStore {
+	t.Helper()
+	qs, err := webserver.NewDataStoreFromData(liveProblems)
+	testutils.AssertNoError(t, err)
+
+	store := livegame.NewLiveGameStore(qs)
+	if notifier != nil {
+		store.SetNotifier(notifier)
+	}
+	if options.QuestionIds == nil {
+		options.QuestionIds = []uuid.UUID{liveProblems[0].Id, liveProblems[1].Id}
+	}
+	err = store.SetupGameOptions(options)
+	testutils.AssertNoError(t, err)
+
+	for _, name := range playerNames {
+		_, err = store.AddPlayer(name)
+		testutils.AssertNoError(t, err)
+	}
+	return store
+}
+
+func TestSubmitAnswer(t *testing.T) {
+	t.Run("Grades correct and incorrect answers", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob")
+
+		got, err := store.SubmitAnswer(ids[0], 0, " 3 ")
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, got.Correct)
+
+		got, err = store.SubmitAnswer(ids[1], 0, "4")
+		testutils.AssertNoError(t, err)
+		testutils.AssertFalse(t, got.Correct)
+
+		answers := store.GetAnswers(0)
+		testutils.AssertEqual(t, len(answers), 2)
+		testutils.AssertEqual(t, answers[0].PlayerId, ids[0])
+		testutils.AssertEqual(t, answers[1].PlayerId, ids[1])
+	})
+
+	t.Run("Accepts any alias", func(t *testing.T) {
+		problem := models.Problem{Id: uuid.New(), Type: models.ProblemTypeText, Question: "Largest economy", Answer: "usa", Aliases: []string{"united states", "us"}}
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{problem}, "Alex", "Bob")
+
+		got, err := store.SubmitAnswer(ids[0], 0, "United States")
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, got.Correct)
+
+		got, err = store.SubmitAnswer(ids[1], 0, "UK")
+		testutils.AssertNoError(t, err)
+		testutils.AssertFalse(t, got.Correct)
+	})
+
+	t.Run("Rejects second answer to the same question", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob")
+
+		_, err := store.SubmitAnswer(ids[0], 0, "4")
+		testutils.AssertNoError(t, err)
+
+		_, err = store.SubmitAnswer(ids[0], 0, "3")
+		var dupErr *types.ErrAnswerAlreadySubmitted
+		testutils.AssertTrue(t, errors.As(err, &dupErr))
+		testutils.AssertFalse(t, store.GetAnswers(0)[0].Correct)
+	})
+
+	t.Run("Rejects answer for a question that is not open", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex")
+
+		_, err := store.SubmitAnswer(ids[0], 1, "4")
+		var notOpenErr *types.ErrQuestionNotOpen
+		testutils.AssertTrue(t, errors.As(err, &notOpenErr))
+	})
+
+	t.Run("Rejects answer from unknown player", func(t *testing.T) {
+		store, _ := createRunningGame(t, "Alex")
+
+		_, err := store.SubmitAnswer(uuid.New(), 0, "3")
+		var notFoundErr *types.ErrPlayerNotFound
+		testutils.AssertTrue(t, errors.As(err, &notFoundErr))
+	})
+
+	t.Run("Rejects answer before game starts", func(t *testing.T) {
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
+		id, err := store.AddPlayer("Alex")
+		testutils.AssertNoError(t, err)
+
+		_, err = store.SubmitAnswer(id, 0, "3")
+		testutils.AssertHasError(t, err)
+	})
+}
@@ -0,0 +1,154 @@
+package livegame
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+// How many finished games an archive without a directory keeps. The oldest are forgotten first.
+const MaxArchivedGames = 100
+
+// Keeps the transcripts of finished games and the event logs of games that are running or finished.
+// If dir is set both are written there and read back when asked for, so they outlive the server
+// and only games still in progress are held in memory.
+type Archive struct {
+	transcripts map[uuid.UUID]models.Transcript
+	eventLogs   map[uuid.UUID]*EventLog
+	finished    []uuid.UUID // Finished games held in memory, oldest first
+	dir         string
+	mutex       sync.RWMutex
+}
+
+// How a transcript is written to disk. Unlike the transcript sent to the host, it keeps the host token.
+type savedTranscript struct {
+	models.Transcript
+	HostToken uuid.UUID `json:"hostToken"`
+}
+
+func NewArchive(dir string) *Archive {
+	return &Archive{
+		transcripts: make(map[uuid.UUID]models.Transcript),
+		eventLogs:   make(map[uuid.UUID]*EventLog),
+		dir:         dir,
+	}
+}
+
+func (a *Archive) Save(transcript models.Transcript) error {
+	if a.dir == "" {
+		a.keep(transcript)
+		return nil
+	}
+	data, err := json.MarshalIndent(savedTranscript{Transcript: transcript, HostToken: transcript.HostToken}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to encode transcript. %v", err)
+	}
+	if err := os.MkdirAll(a.dir, 0755); err == nil {
+		err = os.WriteFile(a.transcriptPath(transcript.GameId), data, 0644)
+	}
+	if err != nil {
+		// Still available until the server stops
+		a.keep(transcript)
+		return fmt.Errorf("Failed to write transcript. %v", err)
+	}
+	return nil
+}
+
+// Holds a finished game in memory, forgetting the oldest once there are more than MaxArchivedGames
+func (a *Archive) keep(transcript models.Transcript) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.transcripts[transcript.GameId] = transcript
+	a.finished = append(a.finished, transcript.GameId)
+	for len(a.finished) > MaxArchivedGames {
+		delete(a.transcripts, a.finished[0])
+		delete(a.eventLogs, a.finished[0])
+		a.finished = a.finished[1:]
+	}
+}
+
+// Gets a transcript, reading it from disk if it is not in memory
+func (a *Archive) Get(gameId uuid.UUID) (models.Transcript, error) {
+	a.mutex.RLock()
+	transcript, ok := a.transcripts[gameId]
+	a.mutex.RUnlock()
+	if ok {
+		return transcript, nil
+	}
+	if a.dir == "" {
+		return models.Transcript{}, &types.ErrTranscriptNotFound{GameId: gameId}
+	}
+	data, err := os.ReadFile(a.transcriptPath(gameId))
+	if err != nil {
+		return models.Transcript{}, &types.ErrTranscriptNotFound{GameId: gameId}
+	}
+	var saved savedTranscript
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return models.Transcript{}, fmt.Errorf("Failed to decode transcript. %v", err)
+	}
+	saved.Transcript.HostToken = saved.HostToken
+	return saved.Transcript, nil
+}
+
+// Starts the event log of a game. Events are written to disk as they happen if the archive has a directory.
+func (a *Archive) NewEventLog(gameId uuid.UUID) *EventLog {
+	path := ""
+	if a.dir != "" {
+		if err := os.MkdirAll(a.dir, 0755); err != nil {
+			log.Printf("Failed to create results directory, event log is kept in memory only. %v", err)
+		} else {
+			path = a.eventLogPath(gameId)
+		}
+	}
+	eventLog := NewEventLog(path)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.eventLogs[gameId] = eventLog
+	return eventLog
+}
+
+// Stops holding the event log of a game that is over in memory. The log of a game that never finished is
+// forgotten, as the game is never archived. Anything already written to disk is kept.
+func (a *Archive) ReleaseEventLog(gameId uuid.UUID, finished bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	// Without a directory a finished game's log lives as long as its transcript
+	if finished && a.dir == "" {
+		return
+	}
+	delete(a.eventLogs, gameId)
+}
+
+// Gets the events logged for a game, reading them from disk if they are not in memory
+func (a *Archive) GetEvents(gameId uuid.UUID) ([]models.GameEvent, error) {
+	a.mutex.RLock()
+	eventLog, ok := a.eventLogs[gameId]
+	a.mutex.RUnlock()
+	if ok {
+		return eventLog.Events(), nil
+	}
+	if a.dir == "" {
+		return nil, &types.ErrEventLogNotFound{GameId: gameId}
+	}
+	events, err := readEventLogFile(a.eventLogPath(gameId))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &types.ErrEventLogNotFound{GameId: gameId}
+	}
+	return events, err
+}
+
+func (a *Archive) transcriptPath(gameId uuid.UUID) string {
+	return filepath.Join(a.dir, gameId.String()+".json")
+}
+
+func (a *Archive) eventLogPath(gameId uuid.UUID) string {
+	return filepath.Join(a.dir, gameId.String()+".events.jsonl")
+}
@@ -0,0 +1,69 @@
+package livegame_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+func TestArchive(t *testing.T) {
+	transcript := models.Transcript{
+		GameId:    uuid.New(),
+		Mode:      "classic",
+		Chat:      []models.ChatEntry{{PlayerName: "Alex", Text: "hi"}},
+		HostToken: uuid.New(),
+	}
+
+	t.Run("Keeps transcripts in memory", func(t *testing.T) {
+		archive := livegame.NewArchive("")
+		testutils.AssertNoError(t, archive.Save(transcript))
+		got, err := archive.Get(transcript.GameId)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, got.Mode, "classic")
+
+		_, err = archive.Get(uuid.New())
+		var notFoundErr *types.ErrTranscriptNotFound
+		testutils.AssertTrue(t, errors.As(err, &notFoundErr))
+	})
+
+	t.Run("Reads transcripts back from disk", func(t *testing.T) {
+		dir := t.TempDir()
+		testutils.AssertNoError(t, livegame.NewArchive(dir).Save(transcript))
+
+		got, err := livegame.NewArchive(dir).Get(transcript.GameId)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, got.GameId, transcript.GameId)
+		testutils.AssertEqual(t, got.Chat[0].Text, "hi")
+		testutils.AssertEqual(t, got.HostToken, transcript.HostToken)
+	})
+
+	t.Run("Only keeps transcripts on disk when it has a directory", func(t *testing.T) {
+		dir := t.TempDir()
+		archive := livegame.NewArchive(dir)
+		testutils.AssertNoError(t, archive.Save(transcript))
+		testutils.AssertNoError(t, os.Remove(filepath.Join(dir, transcript.GameId.String()+".json")))
+
+		_, err := archive.Get(transcript.GameId)
+		var notFoundErr *types.ErrTranscriptNotFound
+		testutils.AssertTrue(t, errors.As(err, &notFoundErr))
+	})
+
+	t.Run("Forgets the oldest games in memory", func(t *testing.T) {
+		archive := livegame.NewArchive("")
+		testutils.AssertNoError(t, archive.Save(transcript))
+		for range livegame.MaxArchivedGames {
+			testutils.AssertNoError(t, archive.Save(models.Transcript{GameId: uuid.New()}))
+		}
+
+		_, err := archive.Get(transcript.GameId)
+		var notFoundErr *types.ErrTranscriptNotFound
+		testutils.AssertTrue(t, errors.As(err, &notFoundErr))
+	})
+}
@@ -0,0 +1,61 @@
+package livegame
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/google/uuid"
+)
+
+// How the choices of a choice question are ordered when sent to players
+type ChoiceShuffle string
+
+const (
+	ChoiceShuffleNone ChoiceShuffle = "none" // Choices keep the order they were written in. Ordering problems are still shuffled.
+	// Everyone sees the same order, shuffled once per question
+	ChoiceShuffleGame ChoiceShuffle = "game"
+	// Each player sees their own order. The host and spectators see the written order.
+	ChoiceShufflePlayer ChoiceShuffle = "player"
+)
+
+func (cs ChoiceShuffle) String() string {
+	return string(cs)
+}
+
+func (cs ChoiceShuffle) IsValid() bool {
+	return cs == ChoiceShuffleNone || cs == ChoiceShuffleGame || cs == ChoiceShufflePlayer
+}
+
+func ParseChoiceShuffle(s string) (ChoiceShuffle, error) {
+	cs := ChoiceShuffle(strings.ToLower(s))
+	if !cs.IsValid() {
+		return "", fmt.Errorf("invalid choice shuffle: %s", s)
+	}
+	return cs, nil
+}
+
+// Orders the choices of a question for a player, or for the host and spectators if playerId is uuid.Nil.
+// The order only depends on the game, question and player, so a player who reconnects sees the same order.
+// Caller must hold the mutex.
+func (lgs *LiveGameStore) orderChoicesLocked(questionNumber int, playerId uuid.UUID, problem models.Problem) []string {
+	choices := slices.Clone(problem.Choices)
+	var seed uint64
+	switch {
+	case lgs.choiceShuffle == ChoiceShufflePlayer && playerId != uuid.Nil:
+		seed = binary.BigEndian.Uint64(playerId[:8]) ^ binary.BigEndian.Uint64(playerId[8:])
+	case lgs.choiceShuffle == ChoiceShuffleGame:
+	// Ordering problems are often written in the correct order, so they are shuffled whatever the setting
+	case problem.Type == models.ProblemTypeOrdering:
+	default:
+		return choices
+	}
+	r := rand.New(rand.NewPCG(lgs.shuffleSeed, seed+uint64(questionNumber)))
+	r.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
+	return choices
+}
@@ -0,0 +1,158 @@
+package livegame_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/google/uuid"
+)
+
+var choiceProblem = models.Problem{
+	Id:       uuid.MustParse("0b1f5c1e-8f0e-4a39-9d4e-3f6a2f1b7c11"),
+	Type:     models.ProblemTypeChoice,
+	Question: "Largest planet",
+	Choices:  []string{"Mercury", "Venus", "Jupiter", "Mars"},
+	Answer:   "Jupiter",
+}
+
+// A choice question followed by a text one
+var choiceProblems = []models.Problem{choiceProblem, liveProblems[0]}
+
+func isPermutation(got []string, want []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(want)))
+}
+
+func TestParseChoiceShuffle(t *testing.T) {
+	shuffle, err := livegame.ParseChoiceShuffle("Player")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, shuffle, livegame.ChoiceShufflePlayer)
+	_, err = livegame.ParseChoiceShuffle("sometimes")
+	testutils.AssertHasError(t, err)
+}
+
+func TestQuestionContent(t *testing.T) {
+	t.Run("Includes what players need to answer", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, _ := createGameWithProblems(t, notifier, livegame.GameOptions{ShuffleChoices: livegame.ChoiceShuffleNone}, choiceProblems, "Alex")
+
+		questions := notifier.messagesOfType(models.MessageTypeNextQuestion)
+		testutils.AssertEqual(t, len(questions), 1)
+		question := questions[0].Content.(models.MessageTypeQuestionContent)
+		testutils.AssertEqual(t, question.Type, "choice")
+		testutils.AssertEqual(t, question.QuestionCount, 2)
+		testutils.AssertTrue(t, slices.Equal(question.Choices, choiceProblem.Choices))
+		testutils.AssertTrue(t, question.Deadline != nil)
+
+		// The answer is never sent
+		data, err := json.Marshal(questions[0])
+		testutils.AssertNoError(t, err)
+		testutils.AssertFalse(t, strings.Contains(strings.ToLower(string(data)), "answer"))
+
+		// Text questions have no choices
+		firstDeadline := *question.Deadline
+		testutils.AssertNoError(t, store.CloseQuestion())
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+		next := notifier.messagesOfType(models.MessageTypeNextQuestion)[1].Content.(models.MessageTypeQuestionContent)
+		testutils.AssertEqual(t, next.Type, "text")
+		testutils.AssertEqual(t, len(next.Choices), 0)
+
+		// A message already sent keeps its own deadline
+		testutils.AssertTrue(t, question.Deadline.Equal(firstDeadline))
+		testutils.AssertFalse(t, next.Deadline.Equal(firstDeadline))
+	})
+
+	t.Run("Paused questions have no deadline", func(t *testing.T) {
+		store, _ := createGameWithProblems(t, nil, livegame.GameOptions{ShuffleChoices: livegame.ChoiceShuffleNone}, choiceProblems, "Alex")
+		testutils.AssertNoError(t, store.PauseGame())
+		question, err := store.CreateQuestionResponse()
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, question.Deadline == nil)
+	})
+
+	t.Run("Shuffled once per game", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createGameWithProblems(t, notifier, livegame.GameOptions{ShuffleChoices: livegame.ChoiceShuffleGame}, choiceProblems, "Alex", "Bob")
+
+		questions := notifier.messagesOfType(models.MessageTypeNextQuestion)
+		testutils.AssertEqual(t, len(questions), 1)
+		choices := questions[0].Content.(models.MessageTypeQuestionContent).Choices
+		testutils.AssertTrue(t, isPermutation(choices, choiceProblem.Choices))
+
+		state, err := store.GetPlayerState(ids[1])
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, slices.Equal(state.Question.Choices, choices))
+	})
+
+	t.Run("Shuffled per player", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createGameWithProblems(t, notifier, livegame.GameOptions{ShuffleChoices: livegame.ChoiceShufflePlayer}, choiceProblems, "Alex", "Bob")
+
+		testutils.AssertEqual(t, len(notifier.messagesOfType(models.MessageTypeNextQuestion)), 0)
+		host := notifier.hostMessagesOfType(models.MessageTypeNextQuestion)
+		testutils.AssertEqual(t, len(host), 1)
+		testutils.AssertTrue(t, slices.Equal(host[0].Content.(models.MessageTypeQuestionContent).Choices, choiceProblem.Choices))
+
+		for _, id := range ids {
+			questions := notifier.playerMessagesOfType(id, models.MessageTypeNextQuestion)
+			testutils.AssertEqual(t, len(questions), 1)
+			choices := questions[0].Content.(models.MessageTypeQuestionContent).Choices
+			testutils.AssertTrue(t, isPermutation(choices, choiceProblem.Choices))
+
+			// A reconnecting player sees the same order again
+			state, err := store.GetPlayerState(id)
+			testutils.AssertNoError(t, err)
+			testutils.AssertTrue(t, slices.Equal(state.Question.Choices, choices))
+		}
+	})
+	t.Run("True/false choices are implied and never shuffled", func(t *testing.T) {
+		problem := models.Problem{Id: uuid.New(), Type: models.ProblemTypeTrueFalse, Question: "The sun is a star", Answer: "true"}
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{ShuffleChoices: livegame.ChoiceShufflePlayer}, []models.Problem{problem}, "Alex")
+
+		state, err := store.GetPlayerState(ids[0])
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, state.Question.Type, "truefalse")
+		testutils.AssertTrue(t, slices.Equal(state.Question.Choices, []string{"True", "False"}))
+	})
+	t.Run("Ordering choices are shuffled even when shuffling is off", func(t *testing.T) {
+		problem := models.Problem{
+			Id:       uuid.New(),
+			Type:     models.ProblemTypeOrdering,
+			Question: "Order these ages",
+			Choices:  []string{"Stone", "Bronze", "Iron", "Steel"},
+			Answer:   `["Stone","Bronze","Iron","Steel"]`,
+		}
+		// Each game has its own order, which can happen to be the written one
+		shuffled := false
+		for range 20 {
+			store, ids := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{problem}, "Alex")
+			question, err := store.CreateQuestionResponse()
+			testutils.AssertNoError(t, err)
+			testutils.AssertTrue(t, isPermutation(question.Choices, problem.Choices))
+			state, err := store.GetPlayerState(ids[0])
+			testutils.AssertNoError(t, err)
+			testutils.AssertTrue(t, slices.Equal(state.Question.Choices, question.Choices))
+			shuffled = shuffled || !slices.Equal(question.Choices, problem.Choices)
+		}
+		testutils.AssertTrue(t, shuffled)
+	})
+	t.Run("Matching questions list the matches", func(t *testing.T) {
+		problem := models.Problem{
+			Id:       uuid.New(),
+			Type:     models.ProblemTypeMatching,
+			Question: "Match each symbol to its element",
+			Choices:  []string{"Na", "K", "Fe"},
+			Answer:   `{"Fe":"Iron","K":"Potassium","Na":"Sodium"}`,
+		}
+		store, _ := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{problem}, "Alex")
+
+		question, err := store.CreateQuestionResponse()
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, slices.Equal(question.Choices, problem.Choices))
+		testutils.AssertTrue(t, slices.Equal(question.Targets, []string{"Iron", "Potassium", "Sodium"}))
+	})
+}
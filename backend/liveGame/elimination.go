@@ -0,0 +1,110 @@
+package livegame
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/google/uuid"
+)
+
+type GameMode string
+
+const (
+	GameModeClassic GameMode = "classic"
+	// A wrong or missing answer knocks the player out of the game
+	GameModeElimination GameMode = "elimination"
+	// Each player moves through the questions at their own pace before an overall deadline
+	GameModeSelfPaced GameMode = "self_paced"
+)
+
+func (gm GameMode) String() string {
+	return string(gm)
+}
+
+func (gm GameMode) IsValid() bool {
+	return gm == GameModeClassic || gm == GameModeElimination || gm == GameModeSelfPaced
+}
+
+func ParseGameMode(s string) (GameMode, error) {
+	gm := GameMode(strings.ToLower(s))
+	if !gm.IsValid() {
+		return "", fmt.Errorf("invalid game mode: %s", s)
+	}
+	return gm, nil
+}
+
+func (lgs *LiveGameStore) GetGameMode() GameMode {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.gameMode
+}
+
+// Eliminates players who missed a question. If every remaining player missed it, nobody is eliminated.
+// Caller must hold the mutex.
+func (lgs *LiveGameStore) eliminatePlayersLocked(questionNumber int) models.EliminationContent {
+	answers := lgs.answers[questionNumber]
+	content := models.EliminationContent{QuestionNumber: questionNumber, Eliminated: []string{}, Remaining: []string{}}
+
+	failed := []int{}
+	active := 0
+	for i, p := range lgs.players {
+		if p.Eliminated {
+			continue
+		}
+		active++
+		if answer, ok := answers[p.Id]; !ok || !answer.Correct {
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) == active {
+		content.Reprieved = len(failed) > 0
+		failed = nil
+	}
+	for _, i := range failed {
+		lgs.players[i].Eliminated = true
+		lgs.players[i].EliminatedOn = questionNumber
+		content.Eliminated = append(content.Eliminated, lgs.players[i].Name)
+	}
+	for _, p := range lgs.players {
+		if !p.Eliminated {
+			content.Remaining = append(content.Remaining, p.Name)
+		}
+	}
+	lgs.rankPlayersLocked()
+	return content
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) activePlayerCountLocked() int {
+	count := 0
+	for _, p := range lgs.players {
+		if !p.Eliminated {
+			count++
+		}
+	}
+	return count
+}
+
+// Adds a tie-breaker question drawn from the questions not yet asked.
+// Returns false if every question has been used. Caller must hold the mutex.
+func (lgs *LiveGameStore) addSuddenDeathQuestionLocked() bool {
+	pool := []uuid.UUID{}
+	for _, problem := range lgs.questionStore.ListProblems() {
+		if !slices.Contains(lgs.questionIds, problem.Id) {
+			pool = append(pool, problem.Id)
+		}
+	}
+	if len(pool) == 0 {
+		return false
+	}
+	lgs.questionIds = append(lgs.questionIds, pool[rand.IntN(len(pool))])
+	return true
+}
+
+// Reports whether the current question is a sudden-death tie-breaker. Caller must hold the mutex.
+func (lgs *LiveGameStore) isSuddenDeathLocked() bool {
+	return lgs.currentQuestion >= lgs.plannedQuestionCount
+}
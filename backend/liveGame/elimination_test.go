@@ -0,0 +1,132 @@
+package livegame_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+func TestParseGameMode(t *testing.T) {
+	mode, err := livegame.ParseGameMode("Elimination")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, mode, livegame.GameModeElimination)
+	_, err = livegame.ParseGameMode("battle royale")
+	testutils.AssertHasError(t, err)
+
+	store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30})
+	testutils.AssertEqual(t, store.GetGameMode(), livegame.GameModeClassic)
+}
+
+func TestElimination(t *testing.T) {
+	elimination := livegame.GameOptions{Mode: livegame.GameModeElimination}
+
+	t.Run("Wrong and missing answers eliminate players", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createGameWithProblems(t, notifier, elimination, liveProblems, "Alex", "Bob", "Cam")
+
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 0, "5")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.CloseQuestion())
+
+		eliminations := notifier.messagesOfType(models.MessageTypeElimination)
+		testutils.AssertEqual(t, len(eliminations), 1)
+		content := eliminations[0].Content.(models.EliminationContent)
+		testutils.AssertTrue(t, slices.Equal(content.Eliminated, []string{"Bob", "Cam"}))
+		testutils.AssertTrue(t, slices.Equal(content.Remaining, []string{"Alex"}))
+
+		bob, _ := store.GetPlayerById(ids[1])
+		testutils.AssertTrue(t, bob.Eliminated)
+		testutils.AssertEqual(t, bob.EliminatedOn, 0)
+
+		// One player left, so the game is over
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+		podium := notifier.messagesOfType(models.MessageTypePodium)[0].Content.(models.PodiumContent).Podium
+		testutils.AssertEqual(t, podium[0].Name, "Alex")
+		testutils.AssertEqual(t, podium[0].Rank, 1)
+		testutils.AssertTrue(t, podium[1].Eliminated)
+	})
+
+	t.Run("Eliminated players cannot answer and are not waited on", func(t *testing.T) {
+		store, ids := createGameWithProblems(t, nil, elimination, liveProblems, "Alex", "Bob", "Cam")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.CloseQuestion())
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+
+		var eliminatedErr *types.ErrPlayerEliminated
+		_, err = store.SubmitAnswer(ids[2], 1, "4")
+		testutils.AssertTrue(t, errors.As(err, &eliminatedErr))
+
+		_, err = store.SubmitAnswer(ids[0], 1, "4")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 1, "4")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+	})
+
+	t.Run("Nobody is eliminated when everyone left misses", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createGameWithProblems(t, notifier, elimination, liveProblems, "Alex", "Bob")
+		_, err := store.SubmitAnswer(ids[0], 0, "1")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 0, "2")
+		testutils.AssertNoError(t, err)
+
+		content := notifier.messagesOfType(models.MessageTypeElimination)[0].Content.(models.EliminationContent)
+		testutils.AssertTrue(t, content.Reprieved)
+		testutils.AssertEqual(t, len(content.Eliminated), 0)
+		testutils.AssertEqual(t, len(content.Remaining), 2)
+	})
+
+	t.Run("Tied players face sudden death from the unused questions", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		// Only the first question is asked, leaving the second for sudden death
+		options := livegame.GameOptions{Mode: livegame.GameModeElimination, QuestionIds: []uuid.UUID{liveProblems[0].Id}}
+		store, ids := createGameWithProblems(t, notifier, options, liveProblems, "Alex", "Bob")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 0, "3")
+		testutils.AssertNoError(t, err)
+
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusRunning)
+		question, err := store.CreateQuestionResponse()
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, question.QuestionNumber, 1)
+		testutils.AssertEqual(t, question.Question, liveProblems[1].Question)
+		testutils.AssertTrue(t, question.SuddenDeath)
+
+		_, err = store.SubmitAnswer(ids[0], 1, "4")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 1, "5")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+	})
+
+	t.Run("Game ends when the question pool runs out", func(t *testing.T) {
+		store, ids := createGameWithProblems(t, nil, elimination, liveProblems, "Alex", "Bob")
+		for question, answer := range []string{"3", "4"} {
+			for _, id := range ids {
+				_, err := store.SubmitAnswer(id, question, answer)
+				testutils.AssertNoError(t, err)
+			}
+			testutils.AssertNoError(t, store.AdvanceQuestion())
+		}
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+		leaderboard := store.GetLeaderboard()
+		testutils.AssertEqual(t, leaderboard[0].Rank, 1)
+		testutils.AssertEqual(t, leaderboard[1].Rank, 1)
+	})
+}
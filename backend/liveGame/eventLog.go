@@ -0,0 +1,124 @@
+package livegame
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+)
+
+// An append-only, timestamped log of what happened in a game.
+// Each event is also appended to the file at path, if set, as a line of JSON.
+type EventLog struct {
+	events []models.GameEvent
+	path   string
+	file   *os.File // Opened by the first event and kept open until Close
+	closed bool
+	mutex  sync.RWMutex
+}
+
+func NewEventLog(path string) *EventLog {
+	return &EventLog{path: path}
+}
+
+func (el *EventLog) Append(kind models.GameEventKind, message models.Message) {
+	event := models.GameEvent{At: time.Now(), Kind: kind, Message: message}
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+	el.events = append(el.events, event)
+
+	if el.path == "" || el.closed {
+		return
+	}
+	if err := el.writeLocked(event); err != nil {
+		log.Printf("Failed to write event log: %v", err)
+	}
+}
+
+// Gets a copy of the events logged so far, oldest first
+func (el *EventLog) Events() []models.GameEvent {
+	el.mutex.RLock()
+	defer el.mutex.RUnlock()
+	return slices.Clone(el.events)
+}
+
+// Closes the log file. Events appended later are only kept in memory.
+func (el *EventLog) Close() error {
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+	el.closed = true
+	if el.file == nil {
+		return nil
+	}
+	err := el.file.Close()
+	el.file = nil
+	return err
+}
+
+// Caller must hold the mutex
+func (el *EventLog) writeLocked(event models.GameEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if el.file == nil {
+		file, err := os.OpenFile(el.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		el.file = file
+	}
+	_, err = el.file.Write(append(data, '\n'))
+	return err
+}
+
+// Reads an event log written by EventLog
+func readEventLogFile(path string) ([]models.GameEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := []models.GameEvent{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event models.GameEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("Failed to decode event %d. %v", len(events)+1, err)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// Closes the event log of the current game once it is over. Caller must hold the mutex.
+func (lgs *LiveGameStore) releaseEventLogLocked() {
+	if lgs.eventLog == nil {
+		return
+	}
+	if err := lgs.eventLog.Close(); err != nil {
+		log.Printf("Failed to close event log: %v", err)
+	}
+	if lgs.archive != nil {
+		lgs.archive.ReleaseEventLog(lgs.gameId, lgs.gameStatus == GameStatusDone)
+	}
+	lgs.eventLog = nil
+}
+
+// Logs a message for the current game. Dropped if no game is set up.
+func (lgs *LiveGameStore) RecordEvent(kind models.GameEventKind, message models.Message) {
+	lgs.mutex.RLock()
+	eventLog := lgs.eventLog
+	lgs.mutex.RUnlock()
+	if eventLog != nil {
+		eventLog.Append(kind, message)
+	}
+}
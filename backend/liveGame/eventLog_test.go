@@ -0,0 +1,85 @@
+package livegame_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+func TestEventLog(t *testing.T) {
+	t.Run("Events are appended in order", func(t *testing.T) {
+		eventLog := livegame.NewEventLog("")
+		eventLog.Append(models.GameEventBroadcast, models.CreateMessage(models.MessageTypeJoin, "Alex", nil))
+		eventLog.Append(models.GameEventHostCommand, models.CreateMessage(models.MessageTypeStartGame, "Host", nil))
+
+		events := eventLog.Events()
+		testutils.AssertEqual(t, len(events), 2)
+		testutils.AssertEqual(t, events[0].Message.Type, models.MessageTypeJoin)
+		testutils.AssertEqual(t, events[1].Kind, models.GameEventHostCommand)
+		testutils.AssertFalse(t, events[1].At.Before(events[0].At))
+	})
+
+	t.Run("Each game is logged to the archive", func(t *testing.T) {
+		dir := t.TempDir()
+		archive := livegame.NewArchive(dir)
+		store := livegame.NewLiveGameStore(nil)
+		store.SetArchive(archive)
+
+		// Nothing is logged before a game is set up
+		store.RecordEvent(models.GameEventBroadcast, models.CreateMessage(models.MessageTypeChat, "Alex", nil))
+		testutils.AssertNoError(t, store.SetupGameOptions(livegame.GameOptions{TimeLimit: 30}))
+		store.RecordEvent(models.GameEventHostCommand, models.CreateMessage(models.MessageTypeStartGame, "Host", nil))
+
+		events, err := archive.GetEvents(store.GetGameId())
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(events), 1)
+
+		// The log outlives the server
+		events, err = livegame.NewArchive(dir).GetEvents(store.GetGameId())
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(events), 1)
+		testutils.AssertEqual(t, events[0].Message.Type, models.MessageTypeStartGame)
+	})
+
+	t.Run("Events after the log is closed are kept in memory only", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		eventLog := livegame.NewEventLog(path)
+		eventLog.Append(models.GameEventBroadcast, models.CreateMessage(models.MessageTypeJoin, "Alex", nil))
+		eventLog.Append(models.GameEventBroadcast, models.CreateMessage(models.MessageTypeJoin, "Bob", nil))
+		testutils.AssertNoError(t, eventLog.Close())
+		eventLog.Append(models.GameEventBroadcast, models.CreateMessage(models.MessageTypeLeave, "Alex", nil))
+
+		testutils.AssertEqual(t, len(eventLog.Events()), 3)
+		data, err := os.ReadFile(path)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, strings.Count(string(data), "\n"), 2)
+	})
+
+	t.Run("Games that never finish are dropped from the archive", func(t *testing.T) {
+		archive := livegame.NewArchive("")
+		store := livegame.NewLiveGameStore(nil)
+		store.SetArchive(archive)
+		testutils.AssertNoError(t, store.SetupGameOptions(livegame.GameOptions{TimeLimit: 30}))
+		gameId := store.GetGameId()
+		store.RecordEvent(models.GameEventHostCommand, models.CreateMessage(models.MessageTypeStartGame, "Host", nil))
+
+		store.KillGame()
+		_, err := archive.GetEvents(gameId)
+		var notFoundErr *types.ErrEventLogNotFound
+		testutils.AssertTrue(t, errors.As(err, &notFoundErr))
+	})
+
+	t.Run("Unknown game", func(t *testing.T) {
+		_, err := livegame.NewArchive(t.TempDir()).GetEvents(uuid.New())
+		var notFoundErr *types.ErrEventLogNotFound
+		testutils.AssertTrue(t, errors.As(err, &notFoundErr))
+	})
+}
@@ -0,0 +1,175 @@
+package livegame
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+)
+
+// Points awarded for a correct answer before any speed or streak adjustment
+const QuestionPoints = 1000
+
+// Number of players shown on the leaderboard
+const LeaderboardSize = 5
+
+// Number of players shown on the final podium
+const PodiumSize = 3
+
+// Gets the players ordered by score, highest first
+func (lgs *LiveGameStore) GetLeaderboard() []LivePlayer {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.sortedPlayersLocked()
+}
+
+// Gets the top of the leaderboard as shown to the host and spectators
+func (lgs *LiveGameStore) GetLeaderboardContent() models.LeaderboardContent {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	sorted := lgs.sortedPlayersLocked()
+	return models.LeaderboardContent{
+		QuestionNumber: lgs.currentQuestion,
+		PlayerCount:    len(sorted),
+		Top:            toLeaderboardEntries(sorted[:min(LeaderboardSize, len(sorted))]),
+	}
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) sortedPlayersLocked() []LivePlayer {
+	sorted := slices.Clone(lgs.players)
+	slices.SortStableFunc(sorted, func(a, b LivePlayer) int {
+		if c := lgs.compareStandingLocked(a, b); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
+
+// Orders two players best first, or 0 if they are tied. Caller must hold the mutex.
+// In an elimination game players still in come first, then those who lasted longest.
+func (lgs *LiveGameStore) compareStandingLocked(a, b LivePlayer) int {
+	if lgs.gameMode == GameModeElimination {
+		if a.Eliminated != b.Eliminated {
+			if a.Eliminated {
+				return 1
+			}
+			return -1
+		}
+		if a.Eliminated && a.EliminatedOn != b.EliminatedOn {
+			return cmp.Compare(b.EliminatedOn, a.EliminatedOn)
+		}
+	}
+	return cmp.Compare(b.Score, a.Score)
+}
+
+// Adds points for the answers to a question and re-ranks the players. Caller must hold the mutex.
+// A missing answer breaks the player's streak.
+func (lgs *LiveGameStore) scoreQuestionLocked(questionNumber int) {
+	answers := lgs.answers[questionNumber]
+	for i, p := range lgs.players {
+		answer, ok := answers[p.Id]
+		lgs.scoreAnswerLocked(i, answer, ok)
+	}
+	lgs.rankPlayersLocked()
+}
+
+// Adds the points for one player's answer. Caller must hold the mutex.
+func (lgs *LiveGameStore) scoreAnswerLocked(index int, answer LiveAnswer, answered bool) {
+	if !answered || answer.Score <= 0 {
+		lgs.players[index].Streak = 0
+		return
+	}
+	scoringFunc := lgs.scoringMode.ScoringFunc()
+	if scoringFunc == nil {
+		scoringFunc = FlatScore
+	}
+	// A partly right answer earns its share of the points but ends the streak
+	if answer.Correct {
+		lgs.players[index].Streak++
+		lgs.players[index].CorrectCount++
+	} else {
+		lgs.players[index].Streak = 0
+	}
+	points := scoringFunc(ScoredAnswer{
+		Correct:      true,
+		ResponseTime: answer.ResponseTime,
+		TimeLimit:    time.Duration(lgs.timeLimit) * time.Second,
+		Streak:       lgs.players[index].Streak,
+	})
+	lgs.players[index].Score += int(math.Round(float64(points) * answer.Score))
+}
+
+// Ranks players by standing. Tied players share a rank. Caller must hold the mutex.
+func (lgs *LiveGameStore) rankPlayersLocked() {
+	for i, p := range lgs.players {
+		rank := 1
+		for _, other := range lgs.players {
+			if lgs.compareStandingLocked(other, p) < 0 {
+				rank++
+			}
+		}
+		lgs.players[i].Rank = rank
+	}
+}
+
+func toLeaderboardEntries(players []LivePlayer) []models.LeaderboardEntry {
+	entries := make([]models.LeaderboardEntry, len(players))
+	for i, p := range players {
+		entries[i] = toLeaderboardEntry(p)
+	}
+	return entries
+}
+
+func toLeaderboardEntry(p LivePlayer) models.LeaderboardEntry {
+	return models.LeaderboardEntry{
+		Name:         p.Name,
+		Score:        p.Score,
+		CorrectCount: p.CorrectCount,
+		Rank:         p.Rank,
+		Eliminated:   p.Eliminated,
+	}
+}
+
+// Sends the top players to the host and each player their own position alongside them
+func (lgs *LiveGameStore) sendLeaderboard(questionNumber int) {
+	lgs.mutex.RLock()
+	sorted := lgs.sortedPlayersLocked()
+	lgs.mutex.RUnlock()
+
+	top := toLeaderboardEntries(sorted[:min(LeaderboardSize, len(sorted))])
+	// The host and spectators see the top of the board without a position of their own
+	board := models.CreateMessage(
+		models.MessageTypeLeaderboard,
+		"System",
+		models.LeaderboardContent{QuestionNumber: questionNumber, PlayerCount: len(sorted), Top: top},
+	)
+	lgs.sendToHost(board)
+	lgs.sendToSpectators(board)
+	for _, p := range sorted {
+		position := toLeaderboardEntry(p)
+		lgs.sendToPlayer(p.Id, models.CreateMessage(
+			models.MessageTypeLeaderboard,
+			"System",
+			models.LeaderboardContent{QuestionNumber: questionNumber, PlayerCount: len(sorted), Top: top, Position: &position},
+		))
+	}
+}
+
+func (lgs *LiveGameStore) broadcastPodium() {
+	lgs.mutex.RLock()
+	sorted := lgs.sortedPlayersLocked()
+	lgs.mutex.RUnlock()
+
+	podium := slices.DeleteFunc(sorted, func(p LivePlayer) bool {
+		return p.Rank > PodiumSize
+	})
+	lgs.broadcast(models.CreateMessage(
+		models.MessageTypePodium,
+		"System",
+		models.PodiumContent{Podium: toLeaderboardEntries(podium)},
+	))
+}
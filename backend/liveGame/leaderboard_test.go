@@ -0,0 +1,78 @@
+package livegame_test
+
+import (
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+)
+
+func TestLeaderboard(t *testing.T) {
+	t.Run("Scores and ranks players when a question closes", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob", "Cara")
+
+		for i, answer := range []string{"3", "4", "3"} {
+			_, err := store.SubmitAnswer(ids[i], 0, answer)
+			testutils.AssertNoError(t, err)
+		}
+
+		leaderboard := store.GetLeaderboard()
+		testutils.AssertEqual(t, len(leaderboard), 3)
+		testutils.AssertEqual(t, leaderboard[0].Name, "Alex")
+		testutils.AssertEqual(t, leaderboard[0].Score, livegame.QuestionPoints)
+		testutils.AssertEqual(t, leaderboard[0].CorrectCount, 1)
+		testutils.AssertEqual(t, leaderboard[0].Rank, 1)
+		testutils.AssertEqual(t, leaderboard[1].Name, "Cara")
+		testutils.AssertEqual(t, leaderboard[1].Rank, 1)
+		testutils.AssertEqual(t, leaderboard[2].Name, "Bob")
+		testutils.AssertEqual(t, leaderboard[2].Score, 0)
+		testutils.AssertEqual(t, leaderboard[2].Rank, 3)
+	})
+
+	t.Run("Sends top players to host and own position to each player", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createRunningGameWithNotifier(t, notifier, "Alex", "Bob")
+
+		_, err := store.SubmitAnswer(ids[0], 0, "4")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 0, "3")
+		testutils.AssertNoError(t, err)
+
+		hostBoards := notifier.hostMessagesOfType(models.MessageTypeLeaderboard)
+		testutils.AssertEqual(t, len(hostBoards), 1)
+		hostBoard := hostBoards[0].Content.(models.LeaderboardContent)
+		testutils.AssertEqual(t, hostBoard.PlayerCount, 2)
+		testutils.AssertEqual(t, hostBoard.Top[0].Name, "Bob")
+		testutils.AssertTrue(t, hostBoard.Position == nil)
+
+		spectatorBoards := notifier.spectatorMessagesOfType(models.MessageTypeLeaderboard)
+		testutils.AssertEqual(t, len(spectatorBoards), 1)
+		testutils.AssertTrue(t, spectatorBoards[0].Content.(models.LeaderboardContent).Position == nil)
+		testutils.AssertEqual(t, store.GetLeaderboardContent().Top[0].Name, "Bob")
+
+		alexBoards := notifier.playerMessagesOfType(ids[0], models.MessageTypeLeaderboard)
+		testutils.AssertEqual(t, len(alexBoards), 1)
+		alexPosition := alexBoards[0].Content.(models.LeaderboardContent).Position
+		testutils.AssertEqual(t, alexPosition.Name, "Alex")
+		testutils.AssertEqual(t, alexPosition.Rank, 2)
+	})
+
+	t.Run("Broadcasts podium when the game is done", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createRunningGameWithNotifier(t, notifier, "Alex")
+
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+		_, err = store.SubmitAnswer(ids[0], 1, "4")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+
+		podiums := notifier.messagesOfType(models.MessageTypePodium)
+		testutils.AssertEqual(t, len(podiums), 1)
+		podium := podiums[0].Content.(models.PodiumContent).Podium
+		testutils.AssertEqual(t, len(podium), 1)
+		testutils.AssertEqual(t, podium[0].Score, 2*livegame.QuestionPoints)
+	})
+}
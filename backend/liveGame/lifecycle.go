@@ -0,0 +1,313 @@
+package livegame
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+// How long results are shown before the game moves to the next question on its own
+const ResultsDisplayTime = 5 * time.Second
+
+func (lgs *LiveGameStore) SetResultsDisplayTime(d time.Duration) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	lgs.resultsDisplayTime = d
+}
+
+func (lgs *LiveGameStore) GetQuestionStatus() QuestionStatus {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.questionStatus
+}
+
+func (lgs *LiveGameStore) GetCurrentQuestion() int {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.currentQuestion
+}
+
+// Stops gathering answers for the current question and broadcasts its results
+func (lgs *LiveGameStore) CloseQuestion() error {
+	return lgs.closeQuestion(lgs.GetCurrentQuestion())
+}
+
+// Moves from the results of the current question to the next question, or ends the game
+func (lgs *LiveGameStore) AdvanceQuestion() error {
+	return lgs.advanceQuestion(lgs.GetCurrentQuestion())
+}
+
+// Starts gathering answers for the current question. Caller must hold the mutex.
+func (lgs *LiveGameStore) openQuestionLocked() {
+	lgs.questionStatus = QuestionStatusGathering
+	lgs.questionStartedAt = time.Now()
+	lgs.stopTimersLocked()
+	lgs.startQuestionTimerLocked(time.Duration(lgs.timeLimit) * time.Second)
+}
+
+// Closes the current question once d has passed. Caller must hold the mutex.
+func (lgs *LiveGameStore) startQuestionTimerLocked(d time.Duration) {
+	questionNumber := lgs.currentQuestion
+	lgs.questionDeadline = time.Now().Add(d)
+	lgs.questionTimer = time.AfterFunc(d, func() {
+		if err := lgs.closeQuestion(questionNumber); err != nil {
+			log.Printf("Question timer: %v", err)
+		}
+	})
+	lgs.startTickTimerLocked()
+}
+
+// Moves past the results of the current question once d has passed. Caller must hold the mutex.
+func (lgs *LiveGameStore) startAdvanceTimerLocked(d time.Duration) {
+	questionNumber := lgs.currentQuestion
+	lgs.advanceDeadline = time.Now().Add(d)
+	lgs.advanceTimer = time.AfterFunc(d, func() {
+		if err := lgs.advanceQuestion(questionNumber); err != nil {
+			log.Printf("Results timer: %v", err)
+		}
+	})
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) stopTimersLocked() {
+	if lgs.questionTimer != nil {
+		lgs.questionTimer.Stop()
+		lgs.questionTimer = nil
+	}
+	if lgs.advanceTimer != nil {
+		lgs.advanceTimer.Stop()
+		lgs.advanceTimer = nil
+	}
+	if lgs.gameTimer != nil {
+		lgs.gameTimer.Stop()
+		lgs.gameTimer = nil
+	}
+	lgs.stopTickTimerLocked()
+}
+
+// Reports whether every connected player has answered the current question. Caller must hold the mutex.
+func (lgs *LiveGameStore) allPlayersAnsweredLocked() bool {
+	answers := lgs.answers[lgs.currentQuestion]
+	waitingOn := 0
+	for _, p := range lgs.players {
+		if !p.Connected || p.Eliminated || p.JoinedOn > lgs.currentQuestion {
+			continue
+		}
+		waitingOn++
+		if _, ok := answers[p.Id]; !ok {
+			return false
+		}
+	}
+	return waitingOn > 0
+}
+
+func (lgs *LiveGameStore) closeQuestion(questionNumber int) error {
+	lgs.mutex.Lock()
+	if lgs.gameMode == GameModeSelfPaced {
+		lgs.mutex.Unlock()
+		return errSelfPaced
+	}
+	if lgs.paused {
+		lgs.mutex.Unlock()
+		return &types.ErrGamePaused{}
+	}
+	if lgs.gameStatus != GameStatusRunning || lgs.questionStatus != QuestionStatusGathering || lgs.currentQuestion != questionNumber {
+		lgs.mutex.Unlock()
+		return &types.ErrQuestionNotOpen{QuestionNumber: questionNumber}
+	}
+	problem, err := lgs.questionStore.GetProblemById(lgs.questionIds[questionNumber])
+	if err != nil {
+		lgs.mutex.Unlock()
+		return fmt.Errorf("QuestionId does not exist %v", lgs.questionIds[questionNumber])
+	}
+	lgs.stopTimersLocked()
+	lgs.questionStatus = QuestionStatusResults
+	lgs.scoreQuestionLocked(questionNumber)
+	var elimination *models.EliminationContent
+	if lgs.gameMode == GameModeElimination {
+		content := lgs.eliminatePlayersLocked(questionNumber)
+		elimination = &content
+	}
+	lgs.startAdvanceTimerLocked(lgs.resultsDisplayTime)
+	lgs.mutex.Unlock()
+
+	lgs.broadcast(models.CreateMessage(
+		models.MessageTypeQuestionClosed,
+		"System",
+		models.QuestionClosedContent{QuestionNumber: questionNumber, Answer: problem.Answer},
+	))
+	lgs.sendQuestionResults(questionNumber)
+	if elimination != nil {
+		lgs.broadcast(models.CreateMessage(models.MessageTypeElimination, "System", *elimination))
+	}
+	lgs.sendLeaderboard(questionNumber)
+	lgs.BroadcastTeamList()
+	return nil
+}
+
+func (lgs *LiveGameStore) advanceQuestion(questionNumber int) error {
+	lgs.mutex.Lock()
+	if lgs.gameMode == GameModeSelfPaced {
+		lgs.mutex.Unlock()
+		return errSelfPaced
+	}
+	if lgs.paused {
+		lgs.mutex.Unlock()
+		return &types.ErrGamePaused{}
+	}
+	if lgs.gameStatus != GameStatusRunning || lgs.questionStatus != QuestionStatusResults || lgs.currentQuestion != questionNumber {
+		lgs.mutex.Unlock()
+		return fmt.Errorf("Cannot advance question %d. Gamestatus: %v, questionStatus: %v", questionNumber, lgs.gameStatus, lgs.questionStatus)
+	}
+	lgs.stopTimersLocked()
+	done := lgs.nextQuestionLocked()
+	lgs.mutex.Unlock()
+
+	lgs.broadcastAdvance(done)
+	return nil
+}
+
+// Moves to the next question, or ends the game after the last one.
+// Returns true if the game ended. Caller must hold the mutex.
+func (lgs *LiveGameStore) nextQuestionLocked() bool {
+	elimination := lgs.gameMode == GameModeElimination
+	if elimination && lgs.activePlayerCountLocked() <= 1 {
+		lgs.endGameLocked()
+		return true
+	}
+	if lgs.currentQuestion+1 >= len(lgs.questionIds) {
+		// Players still tied at the end of an elimination game face sudden-death questions
+		if !elimination || !lgs.addSuddenDeathQuestionLocked() {
+			lgs.endGameLocked()
+			return true
+		}
+	}
+	lgs.currentQuestion++
+	lgs.openQuestionLocked()
+	return false
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) endGameLocked() {
+	lgs.stopTimersLocked()
+	lgs.paused = false
+	lgs.gameStatus = GameStatusDone
+	lgs.questionStatus = QuestionStatusNotStarted
+}
+
+// Broadcasts the question opened by nextQuestionLocked, or the final results if the game ended
+func (lgs *LiveGameStore) broadcastAdvance(done bool) {
+	if !done {
+		lgs.broadcastCurrentQuestion()
+		return
+	}
+	lgs.archiveGame()
+	lgs.broadcastGameStatus(GameStatusDone)
+	lgs.broadcastPodium()
+	lgs.BroadcastTeamList()
+}
+
+// Moves past the current question without scoring it
+func (lgs *LiveGameStore) SkipQuestion() error {
+	lgs.mutex.Lock()
+	if lgs.gameMode == GameModeSelfPaced {
+		lgs.mutex.Unlock()
+		return errSelfPaced
+	}
+	if lgs.paused {
+		lgs.mutex.Unlock()
+		return &types.ErrGamePaused{}
+	}
+	if lgs.gameStatus != GameStatusRunning || lgs.questionStatus == QuestionStatusNotStarted {
+		lgs.mutex.Unlock()
+		return fmt.Errorf("Cannot skip question. Gamestatus: %v, questionStatus: %v", lgs.gameStatus, lgs.questionStatus)
+	}
+	lgs.stopTimersLocked()
+	questionNumber := lgs.currentQuestion
+	skipped := lgs.questionStatus == QuestionStatusGathering
+	if skipped {
+		// Answers to a skipped question do not count
+		delete(lgs.answers, questionNumber)
+	}
+	done := lgs.nextQuestionLocked()
+	lgs.mutex.Unlock()
+
+	if skipped {
+		lgs.broadcast(models.CreateMessage(
+			models.MessageTypeQuestionClosed,
+			"System",
+			models.QuestionClosedContent{QuestionNumber: questionNumber, Skipped: true},
+		))
+	}
+	lgs.broadcastAdvance(done)
+	return nil
+}
+
+// Ends a running or paused game and broadcasts the final results.
+// A question that is still open is not scored.
+func (lgs *LiveGameStore) EndGame() error {
+	lgs.mutex.Lock()
+	if lgs.gameStatus != GameStatusRunning && lgs.gameStatus != GameStatusPaused {
+		lgs.mutex.Unlock()
+		return fmt.Errorf("Cannot end game in status %v", lgs.gameStatus)
+	}
+	// Answers in a self-paced game were scored as they came in
+	if lgs.questionStatus == QuestionStatusGathering && lgs.gameMode != GameModeSelfPaced {
+		delete(lgs.answers, lgs.currentQuestion)
+	}
+	lgs.endGameLocked()
+	lgs.mutex.Unlock()
+
+	lgs.broadcastAdvance(true)
+	return nil
+}
+
+func (lgs *LiveGameStore) broadcastGameStatus(status GameStatus) {
+	lgs.broadcast(models.CreateMessage(models.MessageTypeGameStatus, "System", models.GameStatusContent{Status: string(status)}))
+	if status == GameStatusDone {
+		// Only the host can fetch the results, so only the host is told where they are
+		lgs.sendToHost(models.CreateMessage(
+			models.MessageTypeGameStatus,
+			"System",
+			models.GameStatusContent{Status: string(status), GameId: lgs.GetGameId().String()},
+		))
+	}
+}
+
+// Sends the current question to everyone, followed by its timer.
+// When choices are shuffled per player, each player gets their own copy.
+func (lgs *LiveGameStore) broadcastCurrentQuestion() {
+	msgContent, err := lgs.CreateQuestionResponse()
+	if err != nil {
+		log.Printf("Error creating question message: %v", err)
+		return
+	}
+	msg := models.CreateMessage(models.MessageTypeNextQuestion, "System", msgContent)
+
+	lgs.mutex.RLock()
+	var playerQuestions map[uuid.UUID]models.MessageTypeQuestionContent
+	if lgs.choiceShuffle == ChoiceShufflePlayer && len(msgContent.Choices) > 0 {
+		playerQuestions = make(map[uuid.UUID]models.MessageTypeQuestionContent, len(lgs.players))
+		for _, p := range lgs.players {
+			if question, err := lgs.questionContentLocked(msgContent.QuestionNumber, p.Id); err == nil {
+				playerQuestions[p.Id] = question
+			}
+		}
+	}
+	lgs.mutex.RUnlock()
+
+	if playerQuestions == nil {
+		lgs.broadcast(msg)
+	} else {
+		for id, question := range playerQuestions {
+			lgs.sendToPlayer(id, models.CreateMessage(models.MessageTypeNextQuestion, "System", question))
+		}
+		lgs.sendToHost(msg)
+		lgs.sendToSpectators(msg)
+	}
+	lgs.broadcastTimer()
+}
@@ -0,0 +1,167 @@
+package livegame_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/google/uuid"
+)
+
+// Records every message the store sends
+type recordingNotifier struct {
+	mutex             sync.Mutex
+	messages          []models.Message
+	hostMessages      []models.Message
+	spectatorMessages []models.Message
+	playerMessages    map[uuid.UUID][]models.Message
+}
+
+func (rn *recordingNotifier) BroadcastMessage(message models.Message) {
+	rn.mutex.Lock()
+	defer rn.mutex.Unlock()
+	rn.messages = append(rn.messages, message)
+}
+
+func (rn *recordingNotifier) SendToPlayer(playerId uuid.UUID, message models.Message) bool {
+	rn.mutex.Lock()
+	defer rn.mutex.Unlock()
+	if rn.playerMessages == nil {
+		rn.playerMessages = make(map[uuid.UUID][]models.Message)
+	}
+	rn.playerMessages[playerId] = append(rn.playerMessages[playerId], message)
+	return true
+}
+
+func (rn *recordingNotifier) SendToHost(message models.Message) bool {
+	rn.mutex.Lock()
+	defer rn.mutex.Unlock()
+	rn.hostMessages = append(rn.hostMessages, message)
+	return true
+}
+
+func (rn *recordingNotifier) SendToSpectators(message models.Message) {
+	rn.mutex.Lock()
+	defer rn.mutex.Unlock()
+	rn.spectatorMessages = append(rn.spectatorMessages, message)
+}
+
+func (rn *recordingNotifier) spectatorMessagesOfType(messageType models.MessageType) []models.Message {
+	rn.mutex.Lock()
+	defer rn.mutex.Unlock()
+	return filterMessages(rn.spectatorMessages, messageType)
+}
+
+func (rn *recordingNotifier) hostMessagesOfType(messageType models.MessageType) []models.Message {
+	rn.mutex.Lock()
+	defer rn.mutex.Unlock()
+	return filterMessages(rn.hostMessages, messageType)
+}
+
+func (rn *recordingNotifier) playerMessagesOfType(playerId uuid.UUID, messageType models.MessageType) []models.Message {
+	rn.mutex.Lock()
+	defer rn.mutex.Unlock()
+	return filterMessages(rn.playerMessages[playerId], messageType)
+}
+
+func (rn *recordingNotifier) messagesOfType(messageType models.MessageType) []models.Message {
+	rn.mutex.Lock()
+	defer rn.mutex.Unlock()
+	return filterMessages(rn.messages, messageType)
+}
+
+func filterMessages(messages []models.Message, messageType models.MessageType) []models.Message {
+	found := []models.Message{}
+	for _, m := range messages {
+		if m.Type == messageType {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// Polls until the game reaches the wanted question status or the timeout passes
+func waitForQuestionStatus(t *testing.T, store *livegame.LiveGameStore, want livegame.QuestionStatus, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if store.GetQuestionStatus() == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for question status %v, got %v", want, store.GetQuestionStatus())
+}
+
+func TestQuestionLifecycle(t *testing.T) {
+	t.Run("Start game broadcasts first question", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, _ := createRunningGameWithNotifier(t, notifier, "Alex")
+
+		questions := notifier.messagesOfType(models.MessageTypeNextQuestion)
+		testutils.AssertEqual(t, len(questions), 1)
+		testutils.AssertEqual(t, questions[0].Content.(models.MessageTypeQuestionContent).QuestionNumber, 0)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusGathering)
+	})
+
+	t.Run("Question closes once every player answers", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createRunningGameWithNotifier(t, notifier, "Alex", "Bob")
+
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusGathering)
+
+		_, err = store.SubmitAnswer(ids[1], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+
+		closed := notifier.messagesOfType(models.MessageTypeQuestionClosed)
+		testutils.AssertEqual(t, len(closed), 1)
+		testutils.AssertEqual(t, closed[0].Content.(models.QuestionClosedContent).Answer, "3")
+	})
+
+	t.Run("Host advances through questions until done", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, _ := createRunningGameWithNotifier(t, notifier, "Alex")
+
+		testutils.AssertHasError(t, store.AdvanceQuestion())
+
+		testutils.AssertNoError(t, store.CloseQuestion())
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+		testutils.AssertEqual(t, store.GetCurrentQuestion(), 1)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusGathering)
+
+		testutils.AssertNoError(t, store.CloseQuestion())
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+
+		statuses := notifier.messagesOfType(models.MessageTypeGameStatus)
+		testutils.AssertEqual(t, len(statuses), 1)
+		testutils.AssertEqual(t, statuses[0].Content.(models.GameStatusContent).Status, string(livegame.GameStatusDone))
+	})
+
+	t.Run("Time limit closes question and results advance on their own", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store := setupGameWithNotifier(t, notifier, livegame.GameOptions{TimeLimit: 1}, "Alex")
+		store.SetResultsDisplayTime(200 * time.Millisecond)
+		testutils.AssertNoError(t, store.StartGame())
+
+		waitForQuestionStatus(t, store, livegame.QuestionStatusResults, 2*time.Second)
+		waitForQuestionStatus(t, store, livegame.QuestionStatusGathering, time.Second)
+		testutils.AssertEqual(t, store.GetCurrentQuestion(), 1)
+	})
+
+	t.Run("Removing the last unanswered player closes question", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob")
+
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.RemovePlayerByName("Bob"))
+
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+	})
+}
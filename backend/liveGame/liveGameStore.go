@@ -3,8 +3,10 @@ package livegame
 import (
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/adettinger/go-quizgame/models"
 	"github.com/adettinger/go-quizgame/types"
@@ -13,8 +15,22 @@ import (
 )
 
 type LivePlayer struct {
-	Id   uuid.UUID
-	Name string
+	Id           uuid.UUID
+	Name         string
+	Score        int
+	CorrectCount int
+	Rank         int
+	Streak       int
+	// Lets the player rejoin as themselves after losing their connection
+	ReconnectToken uuid.UUID
+	Connected      bool
+	Muted          bool   // Muted players cannot chat
+	Team           string // Empty unless the game is played in teams
+	// In an elimination game, whether the player is out and on which question
+	Eliminated   bool
+	EliminatedOn int
+	// First question the player may answer. Players who join a running game start from the next question.
+	JoinedOn int
 }
 
 type GameStatus string
@@ -23,6 +39,7 @@ const (
 	GameStatusNotSetup GameStatus = "not_setup"
 	GameStatusSetup    GameStatus = "setup"
 	GameStatusRunning  GameStatus = "running"
+	GameStatusPaused   GameStatus = "paused"
 	GameStatusDone     GameStatus = "done"
 )
 
@@ -34,30 +51,174 @@ const (
 	QuestionStatusResults    QuestionStatus = "results"
 )
 
+type GameOptions struct {
+	TimeLimit   int // Seconds per question
+	QuestionIds []uuid.UUID
+	ScoringMode ScoringMode
+	Teams       []string // Play in these teams. Empty for individual play.
+	TeamScoring TeamScoring
+	Mode        GameMode
+	Duration    int // Seconds a self-paced game lasts. Defaults to the time limit of every question.
+	// How choices are ordered for players. Defaults to the order they were written in.
+	ShuffleChoices ChoiceShuffle
+	CreditMode     models.CreditMode // How partly right answers are scored. Defaults to all or nothing.
+	MaxPlayers     int               // No limit if 0
+	LateJoin       LateJoinPolicy
+}
+
 type LiveGameStore struct {
-	players         []LivePlayer
-	mutex           sync.RWMutex
-	currentQuestion int
-	timeLimit       int
-	questionIds     []uuid.UUID
-	gameStatus      GameStatus
-	questionStore   *webserver.QuestionStore
-	questionStatus  QuestionStatus
+	players              []LivePlayer
+	mutex                sync.RWMutex
+	currentQuestion      int
+	timeLimit            int
+	questionIds          []uuid.UUID
+	plannedQuestionCount int // Questions chosen by the host, before any tie-breakers
+	gameMode             GameMode
+	scoringMode          ScoringMode
+	choiceShuffle        ChoiceShuffle
+	shuffleSeed          uint64
+	creditMode           models.CreditMode
+	teams                []string
+	teamScoring          TeamScoring
+	gameStatus           GameStatus
+	questionStore        *webserver.QuestionStore
+	questionStatus       QuestionStatus
+	answers              map[int]map[uuid.UUID]LiveAnswer // question number -> player id -> answer
+	notifier             Notifier
+	questionStartedAt    time.Time
+	questionTimer        *time.Timer
+	advanceTimer         *time.Timer
+	questionDeadline     time.Time
+	advanceDeadline      time.Time
+	paused               bool
+	pausedAt             time.Time
+	questionRemaining    time.Duration // Time left on the question timer when the game was paused
+	advanceRemaining     time.Duration // Time left on the results timer when the game was paused
+	timerTickInterval    time.Duration
+	tickTimer            *time.Timer
+	tickGeneration       int // Bumped whenever the tick timer stops, so a stale tick does nothing
+	hostToken            uuid.UUID
+	hostConnected        bool
+	resultsDisplayTime   time.Duration
+	reconnectGracePeriod time.Duration
+	disconnectTimers     map[uuid.UUID]*time.Timer
+	bannedNames          map[string]bool // Lowercased names that may not join this room
+	bannedAddresses      map[string]bool
+	maxPlayers           int
+	lobbyLocked          bool
+	lateJoin             LateJoinPolicy
+	gameId               uuid.UUID // Identifies the transcript of the game once it is done
+	startedAt            time.Time
+	chat                 []models.ChatEntry
+	departed             []departedPlayer // Players who left or were kicked once the game started
+	archive              *Archive
+	eventLog             *EventLog
+	// Self-paced games only: each player's question and when they were shown it
+	cursors         map[uuid.UUID]int
+	cursorStartedAt map[uuid.UUID]time.Time
+	duration        time.Duration
+	gameTimer       *time.Timer
+	gameDeadline    time.Time
+	gameRemaining   time.Duration // Time left on the game timer when the game was paused
 }
 
 func NewLiveGameStore(qs *webserver.QuestionStore) *LiveGameStore {
-	return &LiveGameStore{questionStore: qs, gameStatus: GameStatusNotSetup, questionStatus: QuestionStatusNotStarted}
+	return &LiveGameStore{
+		questionStore:        qs,
+		gameStatus:           GameStatusNotSetup,
+		questionStatus:       QuestionStatusNotStarted,
+		answers:              make(map[int]map[uuid.UUID]LiveAnswer),
+		resultsDisplayTime:   ResultsDisplayTime,
+		timerTickInterval:    TimerTickInterval,
+		reconnectGracePeriod: PlayerReconnectGracePeriod,
+		disconnectTimers:     make(map[uuid.UUID]*time.Timer),
+		bannedNames:          make(map[string]bool),
+		bannedAddresses:      make(map[string]bool),
+		cursors:              make(map[uuid.UUID]int),
+		cursorStartedAt:      make(map[uuid.UUID]time.Time),
+	}
 }
 
-func (lgs *LiveGameStore) SetupGameOptions(timeLimit int, qIds []uuid.UUID) error {
+func (lgs *LiveGameStore) SetupGameOptions(options GameOptions) error {
 	lgs.mutex.Lock()
 	defer lgs.mutex.Unlock()
 	if lgs.gameStatus != GameStatusNotSetup && lgs.gameStatus != GameStatusDone {
 		return fmt.Errorf("Cannot setup game. Current status: %v", lgs.gameStatus)
 	}
-	lgs.timeLimit = timeLimit
-	lgs.questionIds = qIds
+	if options.ScoringMode == "" {
+		options.ScoringMode = ScoringModeFlat
+	}
+	if !options.ScoringMode.IsValid() {
+		return fmt.Errorf("Cannot setup game. Invalid scoring mode: %v", options.ScoringMode)
+	}
+	if options.Mode == "" {
+		options.Mode = GameModeClassic
+	}
+	if !options.Mode.IsValid() {
+		return fmt.Errorf("Cannot setup game. Invalid game mode: %v", options.Mode)
+	}
+	if options.ShuffleChoices == "" {
+		options.ShuffleChoices = ChoiceShuffleNone
+	}
+	if !options.ShuffleChoices.IsValid() {
+		return fmt.Errorf("Cannot setup game. Invalid choice shuffle: %v", options.ShuffleChoices)
+	}
+	if options.CreditMode == "" {
+		options.CreditMode = models.CreditModeAllOrNothing
+	}
+	if !options.CreditMode.IsValid() {
+		return fmt.Errorf("Cannot setup game. Invalid credit mode: %v", options.CreditMode)
+	}
+	if options.MaxPlayers < 0 {
+		return fmt.Errorf("Cannot setup game. Invalid max players: %d", options.MaxPlayers)
+	}
+	if options.LateJoin == "" {
+		options.LateJoin = LateJoinPlay
+	}
+	if !options.LateJoin.IsValid() {
+		return fmt.Errorf("Cannot setup game. Invalid late join policy: %v", options.LateJoin)
+	}
+	if err := validateTeams(options.Teams); err != nil {
+		return fmt.Errorf("Cannot setup game. %w", err)
+	}
+	if options.TeamScoring == "" {
+		options.TeamScoring = TeamScoringSum
+	}
+	if !options.TeamScoring.IsValid() {
+		return fmt.Errorf("Cannot setup game. Invalid team scoring: %v", options.TeamScoring)
+	}
+	if options.Duration < 0 {
+		return fmt.Errorf("Cannot setup game. Invalid duration: %d", options.Duration)
+	}
+	if options.Duration == 0 {
+		options.Duration = options.TimeLimit * len(options.QuestionIds)
+	}
+	// The previous game, if any, is over
+	lgs.releaseEventLogLocked()
+	lgs.timeLimit = options.TimeLimit
+	lgs.questionIds = slices.Clone(options.QuestionIds)
+	lgs.plannedQuestionCount = len(options.QuestionIds)
+	lgs.gameMode = options.Mode
+	lgs.duration = time.Duration(options.Duration) * time.Second
+	lgs.scoringMode = options.ScoringMode
+	lgs.choiceShuffle = options.ShuffleChoices
+	lgs.shuffleSeed = rand.Uint64()
+	lgs.creditMode = options.CreditMode
+	lgs.teams = options.Teams
+	lgs.teamScoring = options.TeamScoring
+	lgs.maxPlayers = options.MaxPlayers
+	lgs.lateJoin = options.LateJoin
 	lgs.gameStatus = GameStatusSetup
+	lgs.gameId = uuid.New()
+	lgs.chat = nil
+	lgs.departed = nil
+	if lgs.archive != nil {
+		lgs.eventLog = lgs.archive.NewEventLog(lgs.gameId)
+	} else {
+		lgs.eventLog = NewEventLog("")
+	}
+	lgs.hostToken = uuid.New()
+	lgs.hostConnected = true
 	return nil
 }
 
@@ -65,39 +226,112 @@ func (lgs *LiveGameStore) KillGame() {
 	lgs.mutex.Lock()
 	defer lgs.mutex.Unlock()
 
+	lgs.stopTimersLocked()
+	lgs.stopDisconnectTimersLocked()
+	lgs.releaseEventLogLocked()
 	// Reset all fields to their initial state
 	lgs.players = nil // or make([]LivePlayer, 0)
 	lgs.currentQuestion = 0
 	lgs.timeLimit = 0
 	lgs.questionIds = nil // or make([]uuid.UUID, 0)
+	lgs.plannedQuestionCount = 0
+	lgs.gameMode = ""
+	lgs.duration = 0
+	lgs.cursors = make(map[uuid.UUID]int)
+	lgs.cursorStartedAt = make(map[uuid.UUID]time.Time)
+	lgs.scoringMode = ""
+	lgs.choiceShuffle = ""
+	lgs.creditMode = ""
+	lgs.teams = nil
+	lgs.teamScoring = ""
 	lgs.gameStatus = GameStatusNotSetup
 	lgs.questionStatus = QuestionStatusNotStarted
+	lgs.answers = make(map[int]map[uuid.UUID]LiveAnswer)
+	lgs.paused = false
+	lgs.questionDeadline = time.Time{}
+	lgs.gameDeadline = time.Time{}
+	lgs.hostToken = uuid.Nil
+	lgs.hostConnected = false
+	lgs.bannedNames = make(map[string]bool)
+	lgs.bannedAddresses = make(map[string]bool)
+	lgs.maxPlayers = 0
+	lgs.lobbyLocked = false
+	lgs.lateJoin = ""
+	lgs.gameId = uuid.Nil
+	lgs.chat = nil
+	lgs.departed = nil
 }
 
 func (lgs *LiveGameStore) AddPlayer(name string) (uuid.UUID, error) {
+	return lgs.AddPlayerToTeam(name, "")
+}
+
+// Adds a player to the team they picked. In a team game an empty team auto-assigns the player.
+// Players are turned away if the lobby is locked or full, or the game started and the host does not allow late joins.
+func (lgs *LiveGameStore) AddPlayerToTeam(name string, team string) (uuid.UUID, error) {
 	if lgs.PlayerExistsByName(name) {
 		return uuid.Nil, &types.ErrDuplicatePlayerName{PlayerName: name}
 	}
 	newPlayer := LivePlayer{
-		Id:   lgs.CreatePlayerId(),
-		Name: name,
+		Id:             lgs.CreatePlayerId(),
+		Name:           name,
+		ReconnectToken: uuid.New(),
+		Connected:      true,
 	}
 	lgs.mutex.Lock()
 	defer lgs.mutex.Unlock()
+	if err := lgs.checkCanJoinLocked(); err != nil {
+		return uuid.Nil, err
+	}
+	team, err := lgs.chooseTeamLocked(team)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	newPlayer.Team = team
+	newPlayer.JoinedOn = lgs.firstQuestionForNewPlayerLocked(newPlayer)
 	lgs.players = append(lgs.players, newPlayer)
 	return newPlayer.Id, nil
 }
 
 func (lgs *LiveGameStore) RemovePlayerByName(name string) error {
+	return lgs.removePlayer(name, "")
+}
+
+// Removes a player, keeping them in the transcript's roster if they are departing a started game.
+// An empty departure undoes a join, such as one whose connection never opened.
+func (lgs *LiveGameStore) removePlayer(name string, departure models.PlayerDeparture) error {
 	lgs.mutex.Lock()
-	defer lgs.mutex.Unlock()
-	prevPlayerCount := len(lgs.players)
-	lgs.players = slices.DeleteFunc(lgs.players, func(p LivePlayer) bool {
+	index := slices.IndexFunc(lgs.players, func(p LivePlayer) bool {
 		return p.Name == name
 	})
-	if prevPlayerCount == len(lgs.players) {
+	if index < 0 {
+		lgs.mutex.Unlock()
 		return errors.New("Cannot remove player: Player not found")
 	}
+	if departure != "" && lgs.gameStatus != GameStatusSetup {
+		lgs.departed = append(lgs.departed, departedPlayer{LivePlayer: lgs.players[index], Departure: departure})
+	}
+	lgs.players = slices.Delete(lgs.players, index, index+1)
+	if lgs.gameMode == GameModeSelfPaced {
+		// The departing player may have been the last one still playing
+		done := !lgs.paused && lgs.gameStatus == GameStatusRunning && lgs.allPlayersFinishedLocked()
+		if done {
+			lgs.endGameLocked()
+		}
+		lgs.mutex.Unlock()
+		if done {
+			lgs.broadcastAdvance(true)
+		}
+		return nil
+	}
+	// The departing player may have been the last one we were waiting on
+	closeQuestion := !lgs.paused && lgs.questionStatus == QuestionStatusGathering && lgs.allPlayersAnsweredLocked()
+	questionNumber := lgs.currentQuestion
+	lgs.mutex.Unlock()
+
+	if closeQuestion {
+		lgs.closeQuestion(questionNumber)
+	}
 	return nil
 }
 
@@ -141,6 +375,13 @@ func (lgs *LiveGameStore) GetPlayerById(id uuid.UUID) (LivePlayer, error) {
 	return LivePlayer{}, errors.New("Player not found")
 }
 
+// Caller must hold the mutex. Returns -1 if the player is not found.
+func (lgs *LiveGameStore) playerIndexLocked(id uuid.UUID) int {
+	return slices.IndexFunc(lgs.players, func(p LivePlayer) bool {
+		return p.Id == id
+	})
+}
+
 func (lgs *LiveGameStore) PlayerExistsById(id uuid.UUID) bool {
 	_, err := lgs.GetPlayerById(id)
 	return err == nil
@@ -161,7 +402,19 @@ func (lgs *LiveGameStore) GetGameStatus() GameStatus {
 	return lgs.gameStatus
 }
 
+// Starts the game and broadcasts the first question
 func (lgs *LiveGameStore) StartGame() error {
+	if err := lgs.startGame(); err != nil {
+		return err
+	}
+	lgs.broadcastCurrentQuestion()
+	if progress, err := lgs.GetProgress(); err == nil {
+		lgs.sendProgress(progress)
+	}
+	return nil
+}
+
+func (lgs *LiveGameStore) startGame() error {
 	lgs.mutex.Lock()
 	defer lgs.mutex.Unlock()
 	if lgs.gameStatus != GameStatusSetup {
@@ -171,27 +424,62 @@ func (lgs *LiveGameStore) StartGame() error {
 		return errors.New("Game cannot be started if 0 players")
 	}
 	lgs.gameStatus = GameStatusRunning
-	lgs.questionStatus = QuestionStatusGathering
+	lgs.startedAt = time.Now()
 	lgs.currentQuestion = 0
+	lgs.answers = make(map[int]map[uuid.UUID]LiveAnswer)
+	if lgs.gameMode == GameModeSelfPaced {
+		lgs.startSelfPacedLocked()
+		return nil
+	}
+	lgs.openQuestionLocked()
 
 	return nil
 }
 
+// Creates the current question as the host and spectators see it
 func (lgs *LiveGameStore) CreateQuestionResponse() (models.MessageTypeQuestionContent, error) {
 	lgs.mutex.Lock()
 	defer lgs.mutex.Unlock()
-	if lgs.gameStatus != GameStatusRunning || lgs.questionStatus != QuestionStatusGathering {
+	if (lgs.gameStatus != GameStatusRunning && lgs.gameStatus != GameStatusPaused) || lgs.questionStatus != QuestionStatusGathering {
 		return models.MessageTypeQuestionContent{}, fmt.Errorf("Cannot generate question response if Gamestatus: %v, questionStatus: %v", lgs.gameStatus, lgs.questionStatus)
 	}
-	if lgs.currentQuestion >= len(lgs.questionIds) {
-		return models.MessageTypeQuestionContent{}, fmt.Errorf("Current question out of bounds. CurrentQuestionIndex %d, questionIds: %v", lgs.currentQuestion, lgs.questionIds)
+	return lgs.questionContentLocked(lgs.currentQuestion, uuid.Nil)
+}
+
+// Creates a question as playerId sees it, or as the host and spectators see it if playerId is uuid.Nil.
+// The answer is never included. Caller must hold the mutex.
+func (lgs *LiveGameStore) questionContentLocked(questionNumber int, playerId uuid.UUID) (models.MessageTypeQuestionContent, error) {
+	if questionNumber >= len(lgs.questionIds) {
+		return models.MessageTypeQuestionContent{}, fmt.Errorf("Current question out of bounds. CurrentQuestionIndex %d, questionIds: %v", questionNumber, lgs.questionIds)
 	}
-	problem, err := lgs.questionStore.GetProblemById(lgs.questionIds[lgs.currentQuestion])
+	problem, err := lgs.questionStore.GetProblemById(lgs.questionIds[questionNumber])
 	if err != nil {
-		return models.MessageTypeQuestionContent{}, fmt.Errorf("QuestionId does not exist %v", lgs.questionIds[lgs.currentQuestion])
+		return models.MessageTypeQuestionContent{}, fmt.Errorf("QuestionId does not exist %v", lgs.questionIds[questionNumber])
 	}
-	return models.MessageTypeQuestionContent{
-		QuestionNumber: lgs.currentQuestion,
+	content := models.MessageTypeQuestionContent{
+		QuestionNumber: questionNumber,
+		QuestionCount:  len(lgs.questionIds),
+		Type:           problem.Type.String(),
 		Question:       problem.Question,
-	}, nil
+		SuddenDeath:    lgs.isSuddenDeathLocked(),
+	}
+	if problem.Type == models.ProblemTypeTrueFalse {
+		// Always true then false, however choices are shuffled
+		content.Choices = problem.DisplayChoices()
+	} else if len(problem.Choices) > 0 {
+		content.Choices = lgs.orderChoicesLocked(questionNumber, playerId, problem)
+	}
+	content.Targets = problem.MatchTargets()
+	// A paused question has no deadline until it resumes
+	if !lgs.paused {
+		// Copied, as the message is sent after the lock is released and the timers reset these fields
+		if lgs.gameMode == GameModeSelfPaced {
+			deadline := lgs.gameDeadline
+			content.Deadline = &deadline
+		} else if questionNumber == lgs.currentQuestion && lgs.questionTimer != nil {
+			deadline := lgs.questionDeadline
+			content.Deadline = &deadline
+		}
+	}
+	return content, nil
 }
@@ -6,12 +6,13 @@ import (
 
 	livegame "github.com/adettinger/go-quizgame/liveGame"
 	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/webserver"
 	"github.com/google/uuid"
 )
 
 func TestAddPlayer(t *testing.T) {
 	t.Run("Add multiple players", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		alexId, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -27,7 +28,7 @@ func TestAddPlayer(t *testing.T) {
 	})
 
 	t.Run("Add duplicate players", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		playerId, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -43,7 +44,7 @@ func TestAddPlayer(t *testing.T) {
 
 func TestRemovePlayerByName(t *testing.T) {
 	t.Run("Removes player if found", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		playerUUID, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -56,7 +57,7 @@ func TestRemovePlayerByName(t *testing.T) {
 	})
 
 	t.Run("Error if player not found", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		err := store.RemovePlayerByName("Alex")
 		testutils.AssertHasError(t, err)
@@ -67,14 +68,14 @@ func TestRemovePlayerByName(t *testing.T) {
 
 func TestGetPlayerNameList(t *testing.T) {
 	t.Run("Gets empty list for no names", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		got := store.GetPlayerNameList()
 		testutils.AssertEqual(t, len(got), 0)
 		testutils.AssertTrue(t, slices.Equal(got, []string{}))
 	})
 	t.Run("Gets name list", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		_, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -90,7 +91,7 @@ func TestGetPlayerNameList(t *testing.T) {
 
 func TestGetPlayerByName(t *testing.T) {
 	t.Run("Gets player if exists", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		playerId, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -103,7 +104,7 @@ func TestGetPlayerByName(t *testing.T) {
 	})
 
 	t.Run("Error if player does not exist", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		player, err := store.GetPlayerByName("Alex")
 		testutils.AssertHasError(t, err)
@@ -114,7 +115,7 @@ func TestGetPlayerByName(t *testing.T) {
 
 func TestPlayerExistsByName(t *testing.T) {
 	t.Run("True if player exists", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		_, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -123,7 +124,7 @@ func TestPlayerExistsByName(t *testing.T) {
 	})
 
 	t.Run("False if player does not exists", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		testutils.AssertFalse(t, store.PlayerExistsByName("Alex"))
 	})
@@ -131,7 +132,7 @@ func TestPlayerExistsByName(t *testing.T) {
 
 func TestGetPlayerById(t *testing.T) {
 	t.Run("Gets player if exists", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		playerId, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -144,7 +145,7 @@ func TestGetPlayerById(t *testing.T) {
 	})
 
 	t.Run("Error if player does not exist", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		player, err := store.GetPlayerById(uuid.New())
 		testutils.AssertHasError(t, err)
@@ -155,7 +156,7 @@ func TestGetPlayerById(t *testing.T) {
 
 func TestPlayerExistsById(t *testing.T) {
 	t.Run("True if player exists", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		playerId, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -164,7 +165,7 @@ func TestPlayerExistsById(t *testing.T) {
 	})
 
 	t.Run("False if player does not exists", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		testutils.AssertFalse(t, store.PlayerExistsById(uuid.New()))
 	})
@@ -172,7 +173,7 @@ func TestPlayerExistsById(t *testing.T) {
 
 func TestCreatePlayerId(t *testing.T) {
 	t.Run("gets new unused id", func(t *testing.T) {
-		store := livegame.NewLiveGameStore()
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
 
 		_, err := store.AddPlayer("Alex")
 		testutils.AssertNoError(t, err)
@@ -0,0 +1,108 @@
+package livegame
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+)
+
+// What happens to a player who joins once the game has started
+type LateJoinPolicy string
+
+const (
+	LateJoinReject LateJoinPolicy = "reject"
+	// The player is turned away, but may watch as a spectator
+	LateJoinSpectate LateJoinPolicy = "spectate"
+	// The player joins with no score and answers from the next question.
+	// Nobody can join an elimination game late, so they are offered to spectate instead.
+	LateJoinPlay LateJoinPolicy = "join"
+)
+
+func (ljp LateJoinPolicy) String() string {
+	return string(ljp)
+}
+
+func (ljp LateJoinPolicy) IsValid() bool {
+	return ljp == LateJoinReject || ljp == LateJoinSpectate || ljp == LateJoinPlay
+}
+
+func ParseLateJoinPolicy(s string) (LateJoinPolicy, error) {
+	ljp := LateJoinPolicy(strings.ToLower(s))
+	if !ljp.IsValid() {
+		return "", fmt.Errorf("invalid late join policy: %s", s)
+	}
+	return ljp, nil
+}
+
+// Locks or unlocks the lobby and tells everyone. Players cannot join a locked lobby, but may still reconnect.
+func (lgs *LiveGameStore) SetLobbyLocked(locked bool) error {
+	lgs.mutex.Lock()
+	if lgs.gameStatus == GameStatusNotSetup {
+		lgs.mutex.Unlock()
+		return fmt.Errorf("Cannot change the lobby in status %v", lgs.gameStatus)
+	}
+	lgs.lobbyLocked = locked
+	lgs.mutex.Unlock()
+
+	lgs.BroadcastLobbyStatus()
+	return nil
+}
+
+func (lgs *LiveGameStore) IsLobbyLocked() bool {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.lobbyLocked
+}
+
+func (lgs *LiveGameStore) GetLobbyStatus() models.LobbyStatusContent {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return models.LobbyStatusContent{
+		Locked:      lgs.lobbyLocked,
+		PlayerCount: len(lgs.players),
+		MaxPlayers:  lgs.maxPlayers,
+		LateJoin:    lgs.lateJoin.String(),
+	}
+}
+
+func (lgs *LiveGameStore) BroadcastLobbyStatus() {
+	lgs.broadcast(models.CreateMessage(models.MessageTypeLobbyStatus, "System", lgs.GetLobbyStatus()))
+}
+
+// Checks the lobby rules for a new player. Caller must hold the mutex.
+func (lgs *LiveGameStore) checkCanJoinLocked() error {
+	if lgs.lobbyLocked {
+		return &types.ErrLobbyLocked{}
+	}
+	if lgs.maxPlayers > 0 && len(lgs.players) >= lgs.maxPlayers {
+		return &types.ErrLobbyFull{MaxPlayers: lgs.maxPlayers}
+	}
+	if lgs.gameStatus != GameStatusRunning && lgs.gameStatus != GameStatusPaused {
+		return nil
+	}
+	switch {
+	case lgs.lateJoin == LateJoinSpectate:
+		return &types.ErrGameInProgress{CanSpectate: true}
+	case lgs.lateJoin == LateJoinPlay && lgs.gameMode == GameModeElimination:
+		return &types.ErrGameInProgress{CanSpectate: true}
+	case lgs.lateJoin == LateJoinPlay:
+		return nil
+	}
+	return &types.ErrGameInProgress{}
+}
+
+// Gets the first question a new player may answer. Caller must hold the mutex.
+func (lgs *LiveGameStore) firstQuestionForNewPlayerLocked(player LivePlayer) int {
+	if lgs.gameStatus != GameStatusRunning && lgs.gameStatus != GameStatusPaused {
+		return 0
+	}
+	if lgs.gameMode == GameModeSelfPaced {
+		// Late arrivals start from the beginning, timed from when they join
+		lgs.cursorStartedAt[player.Id] = time.Now()
+		return 0
+	}
+	return lgs.currentQuestion + 1
+}
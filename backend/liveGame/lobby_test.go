@@ -0,0 +1,109 @@
+package livegame_test
+
+import (
+	"errors"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+)
+
+func TestParseLateJoinPolicy(t *testing.T) {
+	policy, err := livegame.ParseLateJoinPolicy("Spectate")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, policy, livegame.LateJoinSpectate)
+	_, err = livegame.ParseLateJoinPolicy("whenever")
+	testutils.AssertHasError(t, err)
+}
+
+func TestLobby(t *testing.T) {
+	t.Run("Max players", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30, MaxPlayers: 2}, "Alex", "Bob")
+		_, err := store.AddPlayer("Cam")
+		var fullErr *types.ErrLobbyFull
+		testutils.AssertTrue(t, errors.As(err, &fullErr))
+		testutils.AssertEqual(t, fullErr.MaxPlayers, 2)
+
+		// A player leaving makes room
+		testutils.AssertNoError(t, store.RemovePlayerByName("Bob"))
+		_, err = store.AddPlayer("Cam")
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("Lock and unlock", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store := setupGameWithNotifier(t, notifier, livegame.GameOptions{TimeLimit: 30}, "Alex")
+		testutils.AssertNoError(t, store.SetLobbyLocked(true))
+		testutils.AssertTrue(t, store.IsLobbyLocked())
+
+		_, err := store.AddPlayer("Bob")
+		var lockedErr *types.ErrLobbyLocked
+		testutils.AssertTrue(t, errors.As(err, &lockedErr))
+
+		statuses := notifier.messagesOfType(models.MessageTypeLobbyStatus)
+		testutils.AssertEqual(t, len(statuses), 1)
+		testutils.AssertEqual(t, statuses[0].Content.(models.LobbyStatusContent), models.LobbyStatusContent{Locked: true, PlayerCount: 1, LateJoin: "join"})
+
+		testutils.AssertNoError(t, store.SetLobbyLocked(false))
+		_, err = store.AddPlayer("Bob")
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("Locking needs a game", func(t *testing.T) {
+		store := livegame.NewLiveGameStore(nil)
+		testutils.AssertHasError(t, store.SetLobbyLocked(true))
+	})
+
+	t.Run("Late joins rejected", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30, LateJoin: livegame.LateJoinReject}, "Alex")
+		testutils.AssertNoError(t, store.StartGame())
+		_, err := store.AddPlayer("Bob")
+		var inProgressErr *types.ErrGameInProgress
+		testutils.AssertTrue(t, errors.As(err, &inProgressErr))
+		testutils.AssertFalse(t, inProgressErr.CanSpectate)
+	})
+
+	t.Run("Late joins spectate", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30, LateJoin: livegame.LateJoinSpectate}, "Alex")
+		testutils.AssertNoError(t, store.StartGame())
+		_, err := store.AddPlayer("Bob")
+		var inProgressErr *types.ErrGameInProgress
+		testutils.AssertTrue(t, errors.As(err, &inProgressErr))
+		testutils.AssertTrue(t, inProgressErr.CanSpectate)
+	})
+
+	t.Run("Late joiners play from the next question", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex")
+		bobId, err := store.AddPlayer("Bob")
+		testutils.AssertNoError(t, err)
+		bob, _ := store.GetPlayerById(bobId)
+		testutils.AssertEqual(t, bob.Score, 0)
+		testutils.AssertEqual(t, bob.JoinedOn, 1)
+
+		_, err = store.SubmitAnswer(bobId, 0, "3")
+		var notOpenErr *types.ErrQuestionNotOpen
+		testutils.AssertTrue(t, errors.As(err, &notOpenErr))
+		state, err := store.GetPlayerState(bobId)
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, state.Question == nil)
+
+		// Bob is not waited on for the current question
+		_, err = store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+
+		testutils.AssertNoError(t, store.AdvanceQuestion())
+		_, err = store.SubmitAnswer(bobId, 1, "4")
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("Nobody joins an elimination game late", func(t *testing.T) {
+		store, _ := createGameWithProblems(t, nil, livegame.GameOptions{Mode: livegame.GameModeElimination}, liveProblems, "Alex")
+		_, err := store.AddPlayer("Bob")
+		var inProgressErr *types.ErrGameInProgress
+		testutils.AssertTrue(t, errors.As(err, &inProgressErr))
+		testutils.AssertTrue(t, inProgressErr.CanSpectate)
+	})
+}
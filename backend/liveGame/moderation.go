@@ -0,0 +1,93 @@
+package livegame
+
+import (
+	"strings"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+// Removes a player from the game. They may join again under any name that is not banned.
+func (lgs *LiveGameStore) KickPlayer(name string) (LivePlayer, error) {
+	player, err := lgs.GetPlayerByName(name)
+	if err != nil {
+		return LivePlayer{}, err
+	}
+	lgs.mutex.Lock()
+	if timer, ok := lgs.disconnectTimers[player.Id]; ok {
+		timer.Stop()
+		delete(lgs.disconnectTimers, player.Id)
+	}
+	lgs.mutex.Unlock()
+
+	if err := lgs.removePlayer(name, models.PlayerDepartureKicked); err != nil {
+		return LivePlayer{}, err
+	}
+	return player, nil
+}
+
+// Kicks a player and keeps their name, and their address if given, out of the room for the rest of the game
+func (lgs *LiveGameStore) BanPlayer(name string, address string) (LivePlayer, error) {
+	player, err := lgs.KickPlayer(name)
+	if err != nil {
+		return LivePlayer{}, err
+	}
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	lgs.bannedNames[strings.ToLower(name)] = true
+	if address != "" {
+		lgs.bannedAddresses[address] = true
+	}
+	return player, nil
+}
+
+// Checks whether a name or address has been banned from the room
+func (lgs *LiveGameStore) CheckBanned(name string, address string) error {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	if lgs.bannedNames[strings.ToLower(name)] || (address != "" && lgs.bannedAddresses[address]) {
+		return &types.ErrPlayerBanned{PlayerName: name}
+	}
+	return nil
+}
+
+// Stops or allows a player's chat messages
+func (lgs *LiveGameStore) SetPlayerMuted(name string, muted bool) (LivePlayer, error) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	for i, p := range lgs.players {
+		if p.Name == name {
+			lgs.players[i].Muted = muted
+			return lgs.players[i], nil
+		}
+	}
+	return LivePlayer{}, &types.ErrPlayerNameNotFound{PlayerName: name}
+}
+
+func (lgs *LiveGameStore) IsPlayerMuted(playerId uuid.UUID) bool {
+	player, err := lgs.GetPlayerById(playerId)
+	return err == nil && player.Muted
+}
+
+// Changes a player's name. The old name is banned so the player cannot take it back.
+func (lgs *LiveGameStore) RenamePlayer(name string, newName string) (LivePlayer, error) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+
+	index := -1
+	for i, p := range lgs.players {
+		if p.Name == newName {
+			return LivePlayer{}, &types.ErrDuplicatePlayerName{PlayerName: newName}
+		}
+		if p.Name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		return LivePlayer{}, &types.ErrPlayerNameNotFound{PlayerName: name}
+	}
+	lgs.players[index].Name = newName
+	lgs.bannedNames[strings.ToLower(name)] = true
+	return lgs.players[index], nil
+}
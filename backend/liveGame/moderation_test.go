@@ -0,0 +1,86 @@
+package livegame_test
+
+import (
+	"errors"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/adettinger/go-quizgame/webserver"
+)
+
+func TestModeration(t *testing.T) {
+	var bannedErr *types.ErrPlayerBanned
+
+	t.Run("Kicked player is removed but may rejoin", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30}, "Alex", "Bob")
+
+		player, err := store.KickPlayer("Alex")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, player.Name, "Alex")
+		testutils.AssertFalse(t, store.PlayerExistsByName("Alex"))
+		testutils.AssertNoError(t, store.CheckBanned("Alex", ""))
+
+		_, err = store.KickPlayer("Alex")
+		testutils.AssertHasError(t, err)
+	})
+
+	t.Run("Banned name and address cannot rejoin", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30}, "Alex", "Bob")
+
+		_, err := store.BanPlayer("Alex", "10.0.0.1")
+		testutils.AssertNoError(t, err)
+		testutils.AssertFalse(t, store.PlayerExistsByName("Alex"))
+
+		testutils.AssertTrue(t, errors.As(store.CheckBanned("alex", ""), &bannedErr))
+		testutils.AssertTrue(t, errors.As(store.CheckBanned("Sam", "10.0.0.1"), &bannedErr))
+		testutils.AssertNoError(t, store.CheckBanned("Sam", "10.0.0.2"))
+	})
+
+	t.Run("Ban without address only bans the name", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30}, "Alex")
+
+		_, err := store.BanPlayer("Alex", "")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.CheckBanned("Sam", ""))
+	})
+
+	t.Run("Mute and unmute", func(t *testing.T) {
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
+		id, err := store.AddPlayer("Alex")
+		testutils.AssertNoError(t, err)
+
+		player, err := store.SetPlayerMuted("Alex", true)
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, player.Muted)
+		testutils.AssertTrue(t, store.IsPlayerMuted(id))
+
+		_, err = store.SetPlayerMuted("Alex", false)
+		testutils.AssertNoError(t, err)
+		testutils.AssertFalse(t, store.IsPlayerMuted(id))
+
+		var notFound *types.ErrPlayerNameNotFound
+		_, err = store.SetPlayerMuted("Nobody", true)
+		testutils.AssertTrue(t, errors.As(err, &notFound))
+	})
+
+	t.Run("Rename keeps the player and bans the old name", func(t *testing.T) {
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
+		id, err := store.AddPlayer("Rude")
+		testutils.AssertNoError(t, err)
+		_, err = store.AddPlayer("Bob")
+		testutils.AssertNoError(t, err)
+
+		var duplicate *types.ErrDuplicatePlayerName
+		_, err = store.RenamePlayer("Rude", "Bob")
+		testutils.AssertTrue(t, errors.As(err, &duplicate))
+
+		player, err := store.RenamePlayer("Rude", "Player 1")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, player.Id, id)
+		testutils.AssertEqual(t, player.Name, "Player 1")
+		testutils.AssertFalse(t, store.PlayerExistsByName("Rude"))
+		testutils.AssertTrue(t, errors.As(store.CheckBanned("Rude", ""), &bannedErr))
+	})
+}
@@ -0,0 +1,66 @@
+package livegame
+
+import (
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/google/uuid"
+)
+
+// Notifier delivers messages generated by the game to connected clients
+type Notifier interface {
+	BroadcastMessage(message models.Message)
+	SendToPlayer(playerId uuid.UUID, message models.Message) bool
+	SendToHost(message models.Message) bool
+	SendToSpectators(message models.Message)
+}
+
+func (lgs *LiveGameStore) SetNotifier(notifier Notifier) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	lgs.notifier = notifier
+}
+
+// Must not be called while holding the store mutex
+func (lgs *LiveGameStore) broadcast(message models.Message) {
+	lgs.mutex.RLock()
+	notifier := lgs.notifier
+	lgs.mutex.RUnlock()
+	if notifier == nil {
+		return
+	}
+	notifier.BroadcastMessage(message)
+}
+
+// Must not be called while holding the store mutex
+func (lgs *LiveGameStore) sendToPlayer(playerId uuid.UUID, message models.Message) {
+	lgs.mutex.RLock()
+	notifier := lgs.notifier
+	lgs.mutex.RUnlock()
+	if notifier == nil {
+		return
+	}
+	notifier.SendToPlayer(playerId, message)
+}
+
+// Must not be called while holding the store mutex
+func (lgs *LiveGameStore) sendToHost(message models.Message) {
+	lgs.mutex.RLock()
+	notifier := lgs.notifier
+	lgs.mutex.RUnlock()
+	if notifier == nil {
+		return
+	}
+	notifier.SendToHost(message)
+}
+
+// Also logs the message, as what spectators see is what a replay shows.
+// Must not be called while holding the store mutex.
+func (lgs *LiveGameStore) sendToSpectators(message models.Message) {
+	lgs.RecordEvent(models.GameEventSpectator, message)
+	lgs.mutex.RLock()
+	notifier := lgs.notifier
+	lgs.mutex.RUnlock()
+	if notifier == nil {
+		return
+	}
+	notifier.SendToSpectators(message)
+}
@@ -0,0 +1,106 @@
+package livegame
+
+import (
+	"fmt"
+	"time"
+)
+
+// Pauses a running game, freezing the question or results timer
+func (lgs *LiveGameStore) PauseGame() error {
+	lgs.mutex.Lock()
+	if lgs.gameStatus != GameStatusRunning {
+		lgs.mutex.Unlock()
+		return fmt.Errorf("Cannot pause game in status %v", lgs.gameStatus)
+	}
+	lgs.gameStatus = GameStatusPaused
+	lgs.pauseLocked()
+	lgs.mutex.Unlock()
+
+	lgs.broadcastGameStatus(GameStatusPaused)
+	lgs.broadcastTimer()
+	return nil
+}
+
+// Resumes a paused game with the time its timer had left
+func (lgs *LiveGameStore) ResumeGame() error {
+	lgs.mutex.Lock()
+	if lgs.gameStatus != GameStatusPaused {
+		lgs.mutex.Unlock()
+		return fmt.Errorf("Cannot resume game in status %v", lgs.gameStatus)
+	}
+	lgs.gameStatus = GameStatusRunning
+	closeQuestion := lgs.resumeLocked()
+	questionNumber := lgs.currentQuestion
+	lgs.mutex.Unlock()
+
+	lgs.broadcastGameStatus(GameStatusRunning)
+	if closeQuestion {
+		lgs.closeQuestion(questionNumber)
+	} else {
+		lgs.broadcastTimer()
+	}
+	return nil
+}
+
+func (lgs *LiveGameStore) IsPaused() bool {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.paused
+}
+
+// Stops the question, results and game timers, remembering how long they had left.
+// Answers are refused until the game is resumed. Caller must hold the mutex.
+func (lgs *LiveGameStore) pauseLocked() {
+	if lgs.paused {
+		return
+	}
+	now := time.Now()
+	lgs.paused = true
+	lgs.pausedAt = now
+	lgs.questionRemaining = 0
+	lgs.advanceRemaining = 0
+	lgs.gameRemaining = 0
+	if lgs.questionTimer != nil {
+		lgs.questionRemaining = max(lgs.questionDeadline.Sub(now), 0)
+	}
+	if lgs.advanceTimer != nil {
+		lgs.advanceRemaining = max(lgs.advanceDeadline.Sub(now), 0)
+	}
+	if lgs.gameTimer != nil {
+		lgs.gameRemaining = max(lgs.gameDeadline.Sub(now), 0)
+	}
+	lgs.stopTimersLocked()
+}
+
+// Restarts any timer stopped by pauseLocked with the time it had left.
+// Returns true if every player answered while the game was paused, so the question should close.
+// Caller must hold the mutex.
+func (lgs *LiveGameStore) resumeLocked() bool {
+	if !lgs.paused {
+		return false
+	}
+	lgs.paused = false
+	// Time spent paused does not count towards response times
+	pausedFor := time.Since(lgs.pausedAt)
+	lgs.questionStartedAt = lgs.questionStartedAt.Add(pausedFor)
+	if lgs.gameStatus != GameStatusRunning {
+		return false
+	}
+	if lgs.gameMode == GameModeSelfPaced {
+		for id, startedAt := range lgs.cursorStartedAt {
+			lgs.cursorStartedAt[id] = startedAt.Add(pausedFor)
+		}
+		lgs.startGameTimerLocked(lgs.gameRemaining)
+		return false
+	}
+	switch lgs.questionStatus {
+	case QuestionStatusGathering:
+		if lgs.allPlayersAnsweredLocked() {
+			return true
+		}
+		lgs.startQuestionTimerLocked(lgs.questionRemaining)
+	case QuestionStatusResults:
+		lgs.startAdvanceTimerLocked(lgs.advanceRemaining)
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package livegame_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+)
+
+func gameStatuses(notifier *recordingNotifier) []string {
+	statuses := []string{}
+	for _, m := range notifier.messagesOfType(models.MessageTypeGameStatus) {
+		statuses = append(statuses, m.Content.(models.GameStatusContent).Status)
+	}
+	return statuses
+}
+
+func TestPauseAndResume(t *testing.T) {
+	t.Run("Pause refuses answers until resumed", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createRunningGameWithNotifier(t, notifier, "Alex", "Bob")
+
+		testutils.AssertNoError(t, store.PauseGame())
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusPaused)
+		testutils.AssertHasError(t, store.PauseGame())
+
+		var pausedErr *types.ErrGamePaused
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertTrue(t, errors.As(err, &pausedErr))
+		testutils.AssertTrue(t, errors.As(store.CloseQuestion(), &pausedErr))
+
+		testutils.AssertNoError(t, store.ResumeGame())
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusRunning)
+		testutils.AssertHasError(t, store.ResumeGame())
+		_, err = store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+
+		testutils.AssertTrue(t, slices.Equal(gameStatuses(notifier), []string{"paused", "running"}))
+	})
+
+	t.Run("Pause freezes the results timer", func(t *testing.T) {
+		store, _ := createRunningGame(t, "Alex")
+		store.SetResultsDisplayTime(50 * time.Millisecond)
+		testutils.AssertNoError(t, store.CloseQuestion())
+
+		testutils.AssertNoError(t, store.PauseGame())
+		time.Sleep(150 * time.Millisecond)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+		testutils.AssertEqual(t, store.GetCurrentQuestion(), 0)
+
+		testutils.AssertNoError(t, store.ResumeGame())
+		waitForQuestionStatus(t, store, livegame.QuestionStatusGathering, time.Second)
+		testutils.AssertEqual(t, store.GetCurrentQuestion(), 1)
+	})
+
+	t.Run("Host reconnecting does not resume a paused game", func(t *testing.T) {
+		store, _ := createRunningGame(t, "Alex")
+		testutils.AssertNoError(t, store.PauseGame())
+
+		store.HostDisconnected()
+		store.HostReconnected()
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusPaused)
+		testutils.AssertTrue(t, store.IsPaused())
+	})
+
+	t.Run("Cannot pause a game that is not running", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30}, "Alex")
+		testutils.AssertHasError(t, store.PauseGame())
+	})
+}
+
+func TestSkipQuestion(t *testing.T) {
+	t.Run("Skipped question is not scored", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createRunningGameWithNotifier(t, notifier, "Alex", "Bob")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+
+		testutils.AssertNoError(t, store.SkipQuestion())
+		testutils.AssertEqual(t, store.GetCurrentQuestion(), 1)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusGathering)
+		testutils.AssertEqual(t, len(store.GetAnswers(0)), 0)
+
+		player, err := store.GetPlayerById(ids[0])
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, player.Score, 0)
+
+		closed := notifier.messagesOfType(models.MessageTypeQuestionClosed)
+		testutils.AssertEqual(t, len(closed), 1)
+		testutils.AssertTrue(t, closed[0].Content.(models.QuestionClosedContent).Skipped)
+		testutils.AssertEqual(t, len(notifier.messagesOfType(models.MessageTypeLeaderboard)), 0)
+	})
+
+	t.Run("Skipping the last question ends the game", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, _ := createRunningGameWithNotifier(t, notifier, "Alex")
+		testutils.AssertNoError(t, store.SkipQuestion())
+		testutils.AssertNoError(t, store.SkipQuestion())
+
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+		testutils.AssertTrue(t, slices.Equal(gameStatuses(notifier), []string{"done"}))
+		testutils.AssertEqual(t, len(notifier.messagesOfType(models.MessageTypePodium)), 1)
+		testutils.AssertHasError(t, store.SkipQuestion())
+	})
+
+	t.Run("Cannot skip while paused", func(t *testing.T) {
+		store, _ := createRunningGame(t, "Alex")
+		testutils.AssertNoError(t, store.PauseGame())
+		testutils.AssertHasError(t, store.SkipQuestion())
+	})
+}
+
+func TestEndGame(t *testing.T) {
+	t.Run("Ending early broadcasts final results", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createRunningGameWithNotifier(t, notifier, "Alex", "Bob")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 0, "5")
+		testutils.AssertNoError(t, err)
+
+		testutils.AssertNoError(t, store.EndGame())
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+		testutils.AssertTrue(t, slices.Equal(gameStatuses(notifier), []string{"done"}))
+
+		podiums := notifier.messagesOfType(models.MessageTypePodium)
+		testutils.AssertEqual(t, len(podiums), 1)
+		podium := podiums[0].Content.(models.PodiumContent).Podium
+		testutils.AssertEqual(t, podium[0].Name, "Alex")
+		testutils.AssertEqual(t, podium[0].Score, livegame.QuestionPoints)
+	})
+
+	t.Run("Paused game can be ended", func(t *testing.T) {
+		store, _ := createRunningGame(t, "Alex")
+		testutils.AssertNoError(t, store.PauseGame())
+		testutils.AssertNoError(t, store.EndGame())
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+		testutils.AssertFalse(t, store.IsPaused())
+	})
+
+	t.Run("Game that has not started cannot be ended", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30}, "Alex")
+		testutils.AssertHasError(t, store.EndGame())
+	})
+}
@@ -0,0 +1,193 @@
+package livegame
+
+import (
+	"log"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+// How long a disconnected player keeps their place in the game
+const PlayerReconnectGracePeriod = 30 * time.Second
+
+func (lgs *LiveGameStore) SetReconnectGracePeriod(d time.Duration) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	lgs.reconnectGracePeriod = d
+}
+
+// Marks a player as disconnected. The player is removed and a leave message broadcast
+// unless they reconnect within the grace period.
+func (lgs *LiveGameStore) DisconnectPlayer(playerId uuid.UUID) error {
+	lgs.mutex.Lock()
+	index := lgs.playerIndexLocked(playerId)
+	if index < 0 {
+		lgs.mutex.Unlock()
+		return &types.ErrPlayerNotFound{PlayerId: playerId}
+	}
+	lgs.players[index].Connected = false
+	if timer, ok := lgs.disconnectTimers[playerId]; ok {
+		timer.Stop()
+	}
+	lgs.disconnectTimers[playerId] = time.AfterFunc(lgs.reconnectGracePeriod, func() {
+		lgs.expireDisconnectedPlayer(playerId)
+	})
+	// Disconnected players are not waited on, and this may have been the last one still answering
+	closeQuestion := lgs.gameMode != GameModeSelfPaced && !lgs.paused &&
+		lgs.questionStatus == QuestionStatusGathering && lgs.allPlayersAnsweredLocked()
+	questionNumber := lgs.currentQuestion
+	lgs.mutex.Unlock()
+
+	if closeQuestion {
+		lgs.closeQuestion(questionNumber)
+	}
+	return nil
+}
+
+// Checks that a token lets name rejoin without changing any state
+func (lgs *LiveGameStore) CheckReconnectToken(name string, token uuid.UUID) error {
+	player, err := lgs.GetPlayerByName(name)
+	if err != nil || token == uuid.Nil || player.ReconnectToken != token {
+		return &types.ErrInvalidReconnectToken{PlayerName: name}
+	}
+	return nil
+}
+
+// Restores a player who rejoins with their reconnect token, keeping their id and score
+func (lgs *LiveGameStore) ReconnectPlayer(name string, token uuid.UUID) (LivePlayer, error) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+
+	for i, p := range lgs.players {
+		if p.Name != name {
+			continue
+		}
+		if token == uuid.Nil || p.ReconnectToken != token {
+			break
+		}
+		if timer, ok := lgs.disconnectTimers[p.Id]; ok {
+			timer.Stop()
+			delete(lgs.disconnectTimers, p.Id)
+		}
+		lgs.players[i].Connected = true
+		return lgs.players[i], nil
+	}
+	return LivePlayer{}, &types.ErrInvalidReconnectToken{PlayerName: name}
+}
+
+// Gets what a player needs to pick up a game where they left off
+func (lgs *LiveGameStore) GetPlayerState(playerId uuid.UUID) (models.PlayerStateContent, error) {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	index := lgs.playerIndexLocked(playerId)
+	if index < 0 {
+		return models.PlayerStateContent{}, &types.ErrPlayerNotFound{PlayerId: playerId}
+	}
+	state := models.PlayerStateContent{
+		GameStatus:     string(lgs.gameStatus),
+		QuestionStatus: string(lgs.questionStatus),
+		QuestionNumber: lgs.currentQuestion,
+		Paused:         lgs.paused,
+		Standing:       toLeaderboardEntry(lgs.players[index]),
+	}
+	if lgs.gameMode == GameModeSelfPaced {
+		// Each player is on their own question and moves on as soon as they answer
+		state.QuestionNumber = lgs.cursors[playerId]
+	} else {
+		_, state.Answered = lgs.answers[lgs.currentQuestion][playerId]
+	}
+	// Players who joined during this question wait for the next one
+	if lgs.questionStatus == QuestionStatusGathering && state.QuestionNumber >= lgs.players[index].JoinedOn {
+		if question, err := lgs.questionContentLocked(state.QuestionNumber, playerId); err == nil {
+			state.Question = &question
+		}
+	}
+	return state, nil
+}
+
+func (lgs *LiveGameStore) expireDisconnectedPlayer(playerId uuid.UUID) {
+	lgs.mutex.Lock()
+	delete(lgs.disconnectTimers, playerId)
+	index := lgs.playerIndexLocked(playerId)
+	if index < 0 || lgs.players[index].Connected {
+		lgs.mutex.Unlock()
+		return
+	}
+	name := lgs.players[index].Name
+	lgs.mutex.Unlock()
+
+	if err := lgs.removePlayer(name, models.PlayerDepartureLeft); err != nil {
+		log.Printf("Failed to remove disconnected player %s: %v", name, err)
+		return
+	}
+	log.Printf("Broadcasting leave message for %s", name)
+	lgs.broadcast(models.CreateMessage(
+		models.MessageTypeLeave,
+		name,
+		models.MessageTextContent{Text: "has left the game"},
+	))
+	lgs.BroadcastTeamList()
+	lgs.BroadcastLobbyStatus()
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) stopDisconnectTimersLocked() {
+	for id, timer := range lgs.disconnectTimers {
+		timer.Stop()
+		delete(lgs.disconnectTimers, id)
+	}
+}
+
+// Gets the token the host uses to reclaim the game after losing their connection
+func (lgs *LiveGameStore) GetHostToken() uuid.UUID {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.hostToken
+}
+
+func (lgs *LiveGameStore) CheckHostToken(token uuid.UUID) error {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	if token == uuid.Nil || lgs.hostToken != token {
+		return &types.ErrInvalidHostToken{}
+	}
+	return nil
+}
+
+func (lgs *LiveGameStore) IsHostConnected() bool {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.hostConnected
+}
+
+// Pauses a running game while the host is away
+func (lgs *LiveGameStore) HostDisconnected() {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	lgs.hostConnected = false
+	if lgs.gameStatus == GameStatusRunning {
+		lgs.pauseLocked()
+	}
+}
+
+// Resumes the game paused when the host lost their connection.
+// A game the host paused themselves stays paused.
+func (lgs *LiveGameStore) HostReconnected() {
+	lgs.mutex.Lock()
+	lgs.hostConnected = true
+	closeQuestion := false
+	resumed := lgs.gameStatus == GameStatusRunning && lgs.paused
+	if lgs.gameStatus == GameStatusRunning {
+		closeQuestion = lgs.resumeLocked()
+	}
+	questionNumber := lgs.currentQuestion
+	lgs.mutex.Unlock()
+
+	if closeQuestion {
+		lgs.closeQuestion(questionNumber)
+	} else if resumed {
+		lgs.broadcastTimer()
+	}
+}
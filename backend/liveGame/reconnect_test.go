@@ -0,0 +1,203 @@
+package livegame_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/adettinger/go-quizgame/webserver"
+	"github.com/google/uuid"
+)
+
+func TestReconnectPlayer(t *testing.T) {
+	t.Run("Player keeps id and score when reconnecting with token", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 0, "3")
+		testutils.AssertNoError(t, err)
+
+		before, err := store.GetPlayerById(ids[0])
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.DisconnectPlayer(ids[0]))
+
+		testutils.AssertNoError(t, store.CheckReconnectToken("Alex", before.ReconnectToken))
+		after, err := store.ReconnectPlayer("Alex", before.ReconnectToken)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, after.Id, before.Id)
+		testutils.AssertEqual(t, after.Score, before.Score)
+		testutils.AssertTrue(t, after.Connected)
+	})
+
+	t.Run("Wrong token is rejected", func(t *testing.T) {
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
+		id, err := store.AddPlayer("Alex")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.DisconnectPlayer(id))
+
+		var tokenErr *types.ErrInvalidReconnectToken
+		testutils.AssertTrue(t, errors.As(store.CheckReconnectToken("Alex", uuid.New()), &tokenErr))
+		_, err = store.ReconnectPlayer("Alex", uuid.New())
+		testutils.AssertTrue(t, errors.As(err, &tokenErr))
+		_, err = store.ReconnectPlayer("Alex", uuid.Nil)
+		testutils.AssertTrue(t, errors.As(err, &tokenErr))
+	})
+
+	t.Run("Player is removed with a leave message after grace period", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
+		store.SetNotifier(notifier)
+		store.SetReconnectGracePeriod(20 * time.Millisecond)
+		id, err := store.AddPlayer("Alex")
+		testutils.AssertNoError(t, err)
+
+		testutils.AssertNoError(t, store.DisconnectPlayer(id))
+		testutils.AssertTrue(t, store.PlayerExistsByName("Alex"))
+		time.Sleep(100 * time.Millisecond)
+
+		testutils.AssertFalse(t, store.PlayerExistsByName("Alex"))
+		leaves := notifier.messagesOfType(models.MessageTypeLeave)
+		testutils.AssertEqual(t, len(leaves), 1)
+		testutils.AssertEqual(t, leaves[0].PlayerName, "Alex")
+
+		// The lobby count drops with them
+		statuses := notifier.messagesOfType(models.MessageTypeLobbyStatus)
+		testutils.AssertEqual(t, len(statuses), 1)
+		testutils.AssertEqual(t, statuses[0].Content.(models.LobbyStatusContent).PlayerCount, 0)
+	})
+
+	t.Run("Reconnecting cancels removal", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
+		store.SetNotifier(notifier)
+		store.SetReconnectGracePeriod(20 * time.Millisecond)
+		id, err := store.AddPlayer("Alex")
+		testutils.AssertNoError(t, err)
+		player, err := store.GetPlayerById(id)
+		testutils.AssertNoError(t, err)
+
+		testutils.AssertNoError(t, store.DisconnectPlayer(id))
+		_, err = store.ReconnectPlayer("Alex", player.ReconnectToken)
+		testutils.AssertNoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+
+		testutils.AssertTrue(t, store.PlayerExistsByName("Alex"))
+		testutils.AssertEqual(t, len(notifier.messagesOfType(models.MessageTypeLeave)), 0)
+	})
+}
+
+func TestGetPlayerState(t *testing.T) {
+	store, ids := createRunningGame(t, "Alex", "Bob")
+	_, err := store.SubmitAnswer(ids[0], 0, "3")
+	testutils.AssertNoError(t, err)
+
+	state, err := store.GetPlayerState(ids[0])
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, state.GameStatus, string(livegame.GameStatusRunning))
+	testutils.AssertEqual(t, state.QuestionNumber, 0)
+	testutils.AssertTrue(t, state.Answered)
+	testutils.AssertTrue(t, state.Question != nil)
+	testutils.AssertEqual(t, state.Question.Question, "1+2")
+
+	state, err = store.GetPlayerState(ids[1])
+	testutils.AssertNoError(t, err)
+	testutils.AssertFalse(t, state.Answered)
+
+	_, err = store.GetPlayerState(uuid.New())
+	testutils.AssertHasError(t, err)
+}
+
+func TestDisconnectedPlayersAreNotAwaited(t *testing.T) {
+	t.Run("Question closes once everyone connected has answered", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob")
+		testutils.AssertNoError(t, store.DisconnectPlayer(ids[1]))
+
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+	})
+
+	t.Run("Question closes when the last player still answering drops", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusGathering)
+
+		testutils.AssertNoError(t, store.DisconnectPlayer(ids[1]))
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+	})
+
+	t.Run("Question stays open when everyone drops", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex")
+		testutils.AssertNoError(t, store.DisconnectPlayer(ids[0]))
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusGathering)
+	})
+}
+
+func TestHostReconnect(t *testing.T) {
+	t.Run("Host token is checked", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30})
+		token := store.GetHostToken()
+		testutils.AssertTrue(t, token != uuid.Nil)
+		testutils.AssertNoError(t, store.CheckHostToken(token))
+
+		var tokenErr *types.ErrInvalidHostToken
+		testutils.AssertTrue(t, errors.As(store.CheckHostToken(uuid.New()), &tokenErr))
+		testutils.AssertTrue(t, errors.As(store.CheckHostToken(uuid.Nil), &tokenErr))
+	})
+
+	t.Run("Game is paused while the host is away", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob")
+		store.SetResultsDisplayTime(50 * time.Millisecond)
+
+		store.HostDisconnected()
+		testutils.AssertTrue(t, store.IsPaused())
+		testutils.AssertFalse(t, store.IsHostConnected())
+
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		var pausedErr *types.ErrGamePaused
+		testutils.AssertTrue(t, errors.As(err, &pausedErr))
+
+		state, err := store.GetPlayerState(ids[0])
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, state.Paused)
+
+		store.HostReconnected()
+		testutils.AssertFalse(t, store.IsPaused())
+		testutils.AssertTrue(t, store.IsHostConnected())
+		_, err = store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("Results timer waits for the host", func(t *testing.T) {
+		store, _ := createRunningGame(t, "Alex")
+		store.SetResultsDisplayTime(50 * time.Millisecond)
+		testutils.AssertNoError(t, store.CloseQuestion())
+
+		store.HostDisconnected()
+		time.Sleep(150 * time.Millisecond)
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+		testutils.AssertEqual(t, store.GetCurrentQuestion(), 0)
+
+		store.HostReconnected()
+		waitForQuestionStatus(t, store, livegame.QuestionStatusGathering, time.Second)
+		testutils.AssertEqual(t, store.GetCurrentQuestion(), 1)
+	})
+
+	t.Run("Question closes on resume if everyone left answered", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+
+		store.HostDisconnected()
+		testutils.AssertNoError(t, store.RemovePlayerByName("Bob"))
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusGathering)
+
+		store.HostReconnected()
+		testutils.AssertEqual(t, store.GetQuestionStatus(), livegame.QuestionStatusResults)
+	})
+}
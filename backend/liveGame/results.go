@@ -0,0 +1,179 @@
+package livegame
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/google/uuid"
+)
+
+// Gets how the players answered a question
+func (lgs *LiveGameStore) GetQuestionResults(questionNumber int) (models.QuestionResultsContent, error) {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.questionResultsLocked(questionNumber)
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) questionResultsLocked(questionNumber int) (models.QuestionResultsContent, error) {
+	if questionNumber < 0 || questionNumber >= len(lgs.questionIds) {
+		return models.QuestionResultsContent{}, fmt.Errorf("Question out of bounds. QuestionIndex %d, questionIds: %v", questionNumber, lgs.questionIds)
+	}
+	problem, err := lgs.questionStore.GetProblemById(lgs.questionIds[questionNumber])
+	if err != nil {
+		return models.QuestionResultsContent{}, fmt.Errorf("QuestionId does not exist %v", lgs.questionIds[questionNumber])
+	}
+
+	answers := lgs.sortedAnswersLocked(questionNumber)
+	results := models.QuestionResultsContent{
+		QuestionNumber: questionNumber,
+		Answer:         problem.Answer,
+		PlayerCount:    len(lgs.players),
+		AnswerCount:    len(answers),
+		Distribution:   answerDistribution(problem, answers),
+		CorrectPlayers: []string{},
+	}
+	var totalResponseTime time.Duration
+	for _, a := range answers {
+		totalResponseTime += a.ResponseTime
+		if !a.Correct {
+			continue
+		}
+		if index := lgs.playerIndexLocked(a.PlayerId); index >= 0 {
+			results.CorrectPlayers = append(results.CorrectPlayers, lgs.players[index].Name)
+		}
+	}
+	if len(answers) > 0 {
+		results.AverageResponseTimeMs = (totalResponseTime / time.Duration(len(answers))).Milliseconds()
+	}
+	return results, nil
+}
+
+// Counts the answers given to a problem. Every choice of a choice problem is listed in the order it was written,
+// followed by any other answers. Text answers are grouped ignoring case and surrounding spaces, most common first.
+// Answers to a numeric problem are grouped by the number they give, so 0.5 and 1/2 count together,
+// and answers to a true/false problem by the value they give, so yes counts as True.
+// Ordering and matching answers are grouped when they give the same sequence or pairs.
+func answerDistribution(problem models.Problem, answers []LiveAnswer) []models.AnswerCount {
+	if problem.Type == models.ProblemTypeMulti {
+		return selectionDistribution(problem, answers)
+	}
+	distribution := []models.AnswerCount{}
+	indexes := make(map[string]int)
+	choices := []string{}
+	if problem.Type == models.ProblemTypeChoice || problem.Type == models.ProblemTypeTrueFalse {
+		choices = problem.DisplayChoices()
+	}
+	for _, c := range choices {
+		indexes[answerKey(problem, c)] = len(distribution)
+		distribution = append(distribution, models.AnswerCount{Answer: c, Correct: problem.IsCorrect(c)})
+	}
+	choiceCount := len(distribution)
+	for _, a := range answers {
+		answer := strings.TrimSpace(a.Answer)
+		key := answerKey(problem, answer)
+		index, ok := indexes[key]
+		if !ok {
+			index = len(distribution)
+			indexes[key] = index
+			distribution = append(distribution, models.AnswerCount{Answer: answer, Correct: a.Correct})
+		}
+		distribution[index].Count++
+	}
+	// Answers that are not one of the choices keep the order they were first given in
+	slices.SortStableFunc(distribution[choiceCount:], func(a, b models.AnswerCount) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	for i := range distribution {
+		distribution[i].Percent = percentOf(distribution[i].Count, len(answers))
+	}
+	return distribution
+}
+
+// Counts how many players picked each choice of a multi-select problem
+func selectionDistribution(problem models.Problem, answers []LiveAnswer) []models.AnswerCount {
+	correct, _ := models.ParseSelection(problem.Answer)
+	distribution := make([]models.AnswerCount, len(problem.Choices))
+	for i, c := range problem.Choices {
+		distribution[i] = models.AnswerCount{
+			Answer:  c,
+			Correct: slices.ContainsFunc(correct, func(a string) bool { return strings.EqualFold(a, c) }),
+		}
+	}
+	for _, a := range answers {
+		selection, err := models.ParseSelection(a.Answer)
+		if err != nil {
+			selection = []string{a.Answer}
+		}
+		for i, c := range problem.Choices {
+			if slices.ContainsFunc(selection, func(s string) bool { return strings.EqualFold(strings.TrimSpace(s), c) }) {
+				distribution[i].Count++
+			}
+		}
+	}
+	for i := range distribution {
+		distribution[i].Percent = percentOf(distribution[i].Count, len(answers))
+	}
+	return distribution
+}
+
+func answerKey(problem models.Problem, answer string) string {
+	if problem.Type == models.ProblemTypeNumeric {
+		if value, unit, err := models.ParseNumber(answer); err == nil {
+			return strconv.FormatFloat(value, 'g', -1, 64) + unit
+		}
+	}
+	if problem.Type == models.ProblemTypeTrueFalse {
+		if value, err := models.ParseTrueFalse(answer); err == nil {
+			return models.FormatTrueFalse(value)
+		}
+	}
+	if problem.Type == models.ProblemTypeOrdering || problem.Type == models.ProblemTypeMatching {
+		// Written the same way whatever spacing the player's device used
+		return models.CanonicalAnswer(problem.Type, strings.ToLower(answer))
+	}
+	return strings.ToLower(answer)
+}
+
+// Rounds to one decimal place
+func percentOf(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(count)*1000/float64(total)) / 10
+}
+
+// Sends the host and spectators how everyone answered a question, and each player whether they got it right
+func (lgs *LiveGameStore) sendQuestionResults(questionNumber int) {
+	lgs.mutex.RLock()
+	results, err := lgs.questionResultsLocked(questionNumber)
+	playerResults := make(map[uuid.UUID]models.PlayerQuestionResultContent, len(lgs.players))
+	for _, p := range lgs.players {
+		answer, answered := lgs.answers[questionNumber][p.Id]
+		playerResults[p.Id] = models.PlayerQuestionResultContent{
+			QuestionNumber: questionNumber,
+			Answered:       answered,
+			Correct:        answered && answer.Correct,
+			Score:          answer.Score,
+		}
+	}
+	lgs.mutex.RUnlock()
+
+	if err != nil {
+		log.Printf("Error creating question results: %v", err)
+		return
+	}
+	msg := models.CreateMessage(models.MessageTypeQuestionResults, "System", results)
+	lgs.sendToHost(msg)
+	lgs.sendToSpectators(msg)
+	for id, result := range playerResults {
+		lgs.sendToPlayer(id, models.CreateMessage(models.MessageTypeQuestionResults, "System", result))
+	}
+}
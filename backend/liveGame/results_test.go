@@ -0,0 +1,154 @@
+package livegame_test
+
+import (
+	"slices"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/google/uuid"
+)
+
+func TestQuestionResults(t *testing.T) {
+	t.Run("Host and spectators get the distribution of choice answers", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createGameWithProblems(t, notifier, livegame.GameOptions{}, choiceProblems, "Alex", "Bob", "Cam", "Dee")
+
+		for i, answer := range []string{"jupiter", "Mars", "Jupiter"} {
+			_, err := store.SubmitAnswer(ids[i], 0, answer)
+			testutils.AssertNoError(t, err)
+		}
+		testutils.AssertNoError(t, store.CloseQuestion())
+
+		messages := notifier.hostMessagesOfType(models.MessageTypeQuestionResults)
+		testutils.AssertEqual(t, len(messages), 1)
+		results := messages[0].Content.(models.QuestionResultsContent)
+		testutils.AssertEqual(t, results.Answer, "Jupiter")
+		testutils.AssertEqual(t, results.PlayerCount, 4)
+		testutils.AssertEqual(t, results.AnswerCount, 3)
+		testutils.AssertTrue(t, slices.Equal(results.CorrectPlayers, []string{"Alex", "Cam"}))
+
+		// Every choice is listed in the order it was written
+		testutils.AssertEqual(t, len(results.Distribution), 4)
+		testutils.AssertEqual(t, results.Distribution[0], models.AnswerCount{Answer: "Mercury"})
+		testutils.AssertEqual(t, results.Distribution[2], models.AnswerCount{Answer: "Jupiter", Count: 2, Percent: 66.7, Correct: true})
+		testutils.AssertEqual(t, results.Distribution[3], models.AnswerCount{Answer: "Mars", Count: 1, Percent: 33.3})
+
+		// Spectators put the same results up for the room
+		spectatorMessages := notifier.spectatorMessagesOfType(models.MessageTypeQuestionResults)
+		testutils.AssertEqual(t, len(spectatorMessages), 1)
+		testutils.AssertEqual(t, spectatorMessages[0].Content.(models.QuestionResultsContent).AnswerCount, 3)
+	})
+
+	t.Run("Distinct text answers are grouped", func(t *testing.T) {
+		store, ids := createRunningGame(t, "Alex", "Bob", "Cam", "Dee")
+		for i, answer := range []string{"4", " three ", "3", "Three"} {
+			_, err := store.SubmitAnswer(ids[i], 0, answer)
+			testutils.AssertNoError(t, err)
+		}
+
+		results, err := store.GetQuestionResults(0)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results.Distribution), 3)
+		testutils.AssertEqual(t, results.Distribution[0], models.AnswerCount{Answer: "three", Count: 2, Percent: 50})
+		testutils.AssertEqual(t, results.Distribution[1], models.AnswerCount{Answer: "4", Count: 1, Percent: 25})
+		testutils.AssertEqual(t, results.Distribution[2], models.AnswerCount{Answer: "3", Count: 1, Percent: 25, Correct: true})
+		testutils.AssertTrue(t, slices.Equal(results.CorrectPlayers, []string{"Cam"}))
+		testutils.AssertTrue(t, results.AverageResponseTimeMs >= 0)
+	})
+
+	t.Run("Numeric answers are grouped by value", func(t *testing.T) {
+		problem := models.Problem{Id: uuid.New(), Type: models.ProblemTypeNumeric, Question: "1/2", Choices: []string{}, Answer: "0.5"}
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{problem}, "Alex", "Bob", "Cam")
+		for i, answer := range []string{"1/2", "0.50", "2"} {
+			_, err := store.SubmitAnswer(ids[i], 0, answer)
+			testutils.AssertNoError(t, err)
+		}
+
+		results, err := store.GetQuestionResults(0)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results.Distribution), 2)
+		testutils.AssertEqual(t, results.Distribution[0], models.AnswerCount{Answer: "1/2", Count: 2, Percent: 66.7, Correct: true})
+		testutils.AssertTrue(t, slices.Equal(results.CorrectPlayers, []string{"Alex", "Bob"}))
+	})
+
+	t.Run("Multi-select picks are counted per choice", func(t *testing.T) {
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{multiProblem}, "Alex", "Bob")
+		for i, answer := range []string{`["Red","Blue","Yellow"]`, `["red","green"]`} {
+			_, err := store.SubmitAnswer(ids[i], 0, answer)
+			testutils.AssertNoError(t, err)
+		}
+
+		results, err := store.GetQuestionResults(0)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results.Distribution), 4)
+		testutils.AssertEqual(t, results.Distribution[0], models.AnswerCount{Answer: "Red", Count: 2, Percent: 100, Correct: true})
+		testutils.AssertEqual(t, results.Distribution[1], models.AnswerCount{Answer: "Green", Count: 1, Percent: 50})
+		testutils.AssertTrue(t, slices.Equal(results.CorrectPlayers, []string{"Alex"}))
+	})
+
+	t.Run("True/false answers are grouped by value", func(t *testing.T) {
+		problem := models.Problem{Id: uuid.New(), Type: models.ProblemTypeTrueFalse, Question: "The sun is a star", Answer: "true"}
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{problem}, "Alex", "Bob", "Cam")
+		for i, answer := range []string{"yes", "T", "no"} {
+			_, err := store.SubmitAnswer(ids[i], 0, answer)
+			testutils.AssertNoError(t, err)
+		}
+
+		results, err := store.GetQuestionResults(0)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results.Distribution), 2)
+		testutils.AssertEqual(t, results.Distribution[0], models.AnswerCount{Answer: "True", Count: 2, Percent: 66.7, Correct: true})
+		testutils.AssertEqual(t, results.Distribution[1], models.AnswerCount{Answer: "False", Count: 1, Percent: 33.3})
+		testutils.AssertTrue(t, slices.Equal(results.CorrectPlayers, []string{"Alex", "Bob"}))
+	})
+
+	t.Run("Ordering answers are grouped by sequence", func(t *testing.T) {
+		problem := models.Problem{
+			Id:       uuid.New(),
+			Type:     models.ProblemTypeOrdering,
+			Question: "Earliest first",
+			Choices:  []string{"Iron", "Stone", "Bronze"},
+			Answer:   `["stone","bronze","iron"]`,
+		}
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{problem}, "Alex", "Bob", "Cam")
+		for i, answer := range []string{`["Stone","Bronze","Iron"]`, `[ "stone", "bronze", "iron" ]`, `["Iron","Stone","Bronze"]`} {
+			_, err := store.SubmitAnswer(ids[i], 0, answer)
+			testutils.AssertNoError(t, err)
+		}
+
+		results, err := store.GetQuestionResults(0)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results.Distribution), 2)
+		testutils.AssertEqual(t, results.Distribution[0], models.AnswerCount{Answer: `["Stone","Bronze","Iron"]`, Count: 2, Percent: 66.7, Correct: true})
+		testutils.AssertEqual(t, results.Distribution[1].Count, 1)
+	})
+
+	t.Run("Players only see their own result", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createRunningGameWithNotifier(t, notifier, "Alex", "Bob", "Cam")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 0, "5")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.CloseQuestion())
+
+		want := map[uuid.UUID]models.PlayerQuestionResultContent{
+			ids[0]: {QuestionNumber: 0, Answered: true, Correct: true, Score: 1},
+			ids[1]: {QuestionNumber: 0, Answered: true},
+			ids[2]: {QuestionNumber: 0},
+		}
+		for id, result := range want {
+			messages := notifier.playerMessagesOfType(id, models.MessageTypeQuestionResults)
+			testutils.AssertEqual(t, len(messages), 1)
+			testutils.AssertEqual(t, messages[0].Content.(models.PlayerQuestionResultContent), result)
+		}
+	})
+
+	t.Run("Unknown question", func(t *testing.T) {
+		store, _ := createRunningGame(t, "Alex")
+		_, err := store.GetQuestionResults(5)
+		testutils.AssertHasError(t, err)
+	})
+}
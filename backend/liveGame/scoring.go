@@ -0,0 +1,89 @@
+package livegame
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ScoringMode string
+
+const (
+	ScoringModeFlat   ScoringMode = "flat"
+	ScoringModeSpeed  ScoringMode = "speed"
+	ScoringModeStreak ScoringMode = "streak"
+)
+
+// Bonus points for each consecutive correct answer after the first
+const StreakBonus = 100
+
+// Most streak bonuses a single answer can earn
+const MaxStreakBonuses = 5
+
+// Facts about a graded answer used to score it
+type ScoredAnswer struct {
+	Correct      bool
+	ResponseTime time.Duration
+	TimeLimit    time.Duration
+	Streak       int // Consecutive correct answers including this one
+}
+
+// Gives the points earned by one answer
+type ScoringFunc func(answer ScoredAnswer) int
+
+var scoringFuncs = map[ScoringMode]ScoringFunc{
+	ScoringModeFlat:   FlatScore,
+	ScoringModeSpeed:  SpeedScore,
+	ScoringModeStreak: StreakScore,
+}
+
+func (sm ScoringMode) String() string {
+	return string(sm)
+}
+
+func (sm ScoringMode) IsValid() bool {
+	_, ok := scoringFuncs[sm]
+	return ok
+}
+
+func (sm ScoringMode) ScoringFunc() ScoringFunc {
+	return scoringFuncs[sm]
+}
+
+func ParseScoringMode(s string) (ScoringMode, error) {
+	sm := ScoringMode(strings.ToLower(s))
+	if !sm.IsValid() {
+		return "", fmt.Errorf("invalid scoring mode: %s", s)
+	}
+	return sm, nil
+}
+
+// Every correct answer is worth the same
+func FlatScore(answer ScoredAnswer) int {
+	if !answer.Correct {
+		return 0
+	}
+	return QuestionPoints
+}
+
+// Correct answers are worth between half and all of QuestionPoints, decreasing linearly over the time limit
+func SpeedScore(answer ScoredAnswer) int {
+	if !answer.Correct {
+		return 0
+	}
+	if answer.TimeLimit <= 0 {
+		return QuestionPoints
+	}
+	remaining := 1 - float64(answer.ResponseTime)/float64(answer.TimeLimit)
+	remaining = max(0, min(1, remaining))
+	return QuestionPoints/2 + int(remaining*QuestionPoints/2)
+}
+
+// Speed scoring plus a bonus for each consecutive correct answer
+func StreakScore(answer ScoredAnswer) int {
+	if !answer.Correct {
+		return 0
+	}
+	bonuses := min(max(answer.Streak-1, 0), MaxStreakBonuses)
+	return SpeedScore(answer) + bonuses*StreakBonus
+}
@@ -0,0 +1,154 @@
+package livegame_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+func TestParseScoringMode(t *testing.T) {
+	got, err := livegame.ParseScoringMode("Speed")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, got, livegame.ScoringModeSpeed)
+
+	_, err = livegame.ParseScoringMode("fastest")
+	testutils.AssertHasError(t, err)
+}
+
+func TestScoringFuncs(t *testing.T) {
+	limit := 30 * time.Second
+	cases := []struct {
+		name   string
+		mode   livegame.ScoringMode
+		answer livegame.ScoredAnswer
+		want   int
+	}{
+		{"flat correct", livegame.ScoringModeFlat, livegame.ScoredAnswer{Correct: true, ResponseTime: 20 * time.Second, TimeLimit: limit}, livegame.QuestionPoints},
+		{"flat wrong", livegame.ScoringModeFlat, livegame.ScoredAnswer{Correct: false, TimeLimit: limit}, 0},
+		{"speed instant", livegame.ScoringModeSpeed, livegame.ScoredAnswer{Correct: true, ResponseTime: 0, TimeLimit: limit}, livegame.QuestionPoints},
+		{"speed halfway", livegame.ScoringModeSpeed, livegame.ScoredAnswer{Correct: true, ResponseTime: 15 * time.Second, TimeLimit: limit}, 750},
+		{"speed past limit", livegame.ScoringModeSpeed, livegame.ScoredAnswer{Correct: true, ResponseTime: 40 * time.Second, TimeLimit: limit}, livegame.QuestionPoints / 2},
+		{"speed wrong", livegame.ScoringModeSpeed, livegame.ScoredAnswer{Correct: false, TimeLimit: limit}, 0},
+		{"streak first answer", livegame.ScoringModeStreak, livegame.ScoredAnswer{Correct: true, TimeLimit: limit, Streak: 1}, livegame.QuestionPoints},
+		{"streak third answer", livegame.ScoringModeStreak, livegame.ScoredAnswer{Correct: true, TimeLimit: limit, Streak: 3}, livegame.QuestionPoints + 2*livegame.StreakBonus},
+		{"streak bonus capped", livegame.ScoringModeStreak, livegame.ScoredAnswer{Correct: true, TimeLimit: limit, Streak: 50}, livegame.QuestionPoints + livegame.MaxStreakBonuses*livegame.StreakBonus},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.AssertEqual(t, tt.mode.ScoringFunc()(tt.answer), tt.want)
+		})
+	}
+}
+
+func TestStreakScoringInGame(t *testing.T) {
+	notifier := &recordingNotifier{}
+	store := setupGameWithNotifier(t, notifier, livegame.GameOptions{TimeLimit: 30, ScoringMode: livegame.ScoringModeStreak}, "Alex")
+	testutils.AssertNoError(t, store.StartGame())
+	player, err := store.GetPlayerByName("Alex")
+	testutils.AssertNoError(t, err)
+
+	_, err = store.SubmitAnswer(player.Id, 0, "3")
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, store.AdvanceQuestion())
+	_, err = store.SubmitAnswer(player.Id, 1, "4")
+	testutils.AssertNoError(t, err)
+
+	player, err = store.GetPlayerByName("Alex")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, player.Streak, 2)
+	// Answers arrive almost instantly, so both are worth close to full points and the second earns a bonus
+	testutils.AssertTrue(t, player.Score > 2*livegame.QuestionPoints)
+}
+
+func TestSetupGameOptionsRejectsInvalidScoringMode(t *testing.T) {
+	store := livegame.NewLiveGameStore(nil)
+	err := store.SetupGameOptions(livegame.GameOptions{TimeLimit: 30, ScoringMode: "fastest"})
+	testutils.AssertHasError(t, err)
+	testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusNotSetup)
+}
+
+var multiProblem = models.Problem{
+	Id:       uuid.MustParse("5a3c2e0f-51f4-4f7e-9a38-2b2f0c7d9e41"),
+	Type:     models.ProblemTypeMulti,
+	Question: "Primary colours",
+	Choices:  []string{"Red", "Green", "Blue", "Yellow"},
+	Answer:   `["red","blue","yellow"]`,
+}
+
+func TestPartialCredit(t *testing.T) {
+	t.Run("All or nothing", func(t *testing.T) {
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{multiProblem}, "Alex", "Bob")
+		answer, err := store.SubmitAnswer(ids[0], 0, `["red","blue"]`)
+		testutils.AssertNoError(t, err)
+		testutils.AssertFalse(t, answer.Correct)
+		testutils.AssertEqual(t, answer.Score, 0.0)
+		_, err = store.SubmitAnswer(ids[1], 0, `["red","blue","yellow"]`)
+		testutils.AssertNoError(t, err)
+
+		alex, _ := store.GetPlayerById(ids[0])
+		bob, _ := store.GetPlayerById(ids[1])
+		testutils.AssertEqual(t, alex.Score, 0)
+		testutils.AssertEqual(t, bob.Score, livegame.QuestionPoints)
+	})
+
+	t.Run("Proportional", func(t *testing.T) {
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{CreditMode: models.CreditModeProportional}, []models.Problem{multiProblem}, "Alex", "Bob")
+		answer, err := store.SubmitAnswer(ids[0], 0, `["red","blue"]`)
+		testutils.AssertNoError(t, err)
+		testutils.AssertFalse(t, answer.Correct)
+		testutils.AssertEqual(t, answer.Score, 2.0/3)
+		_, err = store.SubmitAnswer(ids[1], 0, `["green"]`)
+		testutils.AssertNoError(t, err)
+
+		alex, _ := store.GetPlayerById(ids[0])
+		testutils.AssertEqual(t, alex.Score, 667)
+		testutils.AssertEqual(t, alex.CorrectCount, 0)
+		testutils.AssertEqual(t, alex.Streak, 0)
+	})
+
+	t.Run("Ordering earns credit for each item in place", func(t *testing.T) {
+		problem := models.Problem{
+			Id:       uuid.New(),
+			Type:     models.ProblemTypeOrdering,
+			Question: "Earliest first",
+			Choices:  []string{"Iron", "Stone", "Bronze", "Steel"},
+			Answer:   `["stone","bronze","iron","steel"]`,
+		}
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{CreditMode: models.CreditModeProportional}, []models.Problem{problem}, "Alex")
+		answer, err := store.SubmitAnswer(ids[0], 0, `["Stone","Iron","Bronze","Steel"]`)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, answer.Score, 0.5)
+
+		alex, _ := store.GetPlayerById(ids[0])
+		testutils.AssertEqual(t, alex.Score, livegame.QuestionPoints/2)
+	})
+
+	t.Run("Answers of the wrong shape can be sent again", func(t *testing.T) {
+		problem := models.Problem{
+			Id:       uuid.New(),
+			Type:     models.ProblemTypeMatching,
+			Question: "Match each symbol to its element",
+			Choices:  []string{"Na", "K"},
+			Answer:   `{"K":"Potassium","Na":"Sodium"}`,
+		}
+		store, ids := createGameWithProblems(t, nil, livegame.GameOptions{}, []models.Problem{problem}, "Alex")
+		_, err := store.SubmitAnswer(ids[0], 0, "Sodium")
+		var invalidErr *types.ErrInvalidAnswer
+		testutils.AssertTrue(t, errors.As(err, &invalidErr))
+
+		answer, err := store.SubmitAnswer(ids[0], 0, `{"Na":"Sodium","K":"Potassium"}`)
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, answer.Correct)
+	})
+
+	t.Run("Invalid credit mode", func(t *testing.T) {
+		store := livegame.NewLiveGameStore(nil)
+		testutils.AssertHasError(t, store.SetupGameOptions(livegame.GameOptions{TimeLimit: 30, CreditMode: "most"}))
+	})
+}
@@ -0,0 +1,190 @@
+package livegame
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+var errSelfPaced = errors.New("Questions move on per player in a self-paced game")
+
+// Starts the game timer of a self-paced game. Every player begins on the first question.
+// Caller must hold the mutex.
+func (lgs *LiveGameStore) startSelfPacedLocked() {
+	now := time.Now()
+	lgs.questionStatus = QuestionStatusGathering
+	lgs.questionStartedAt = now
+	lgs.cursors = make(map[uuid.UUID]int)
+	lgs.cursorStartedAt = make(map[uuid.UUID]time.Time)
+	for _, p := range lgs.players {
+		lgs.cursorStartedAt[p.Id] = now
+	}
+	lgs.stopTimersLocked()
+	lgs.startGameTimerLocked(lgs.duration)
+}
+
+// Ends a self-paced game once d has passed. Caller must hold the mutex.
+func (lgs *LiveGameStore) startGameTimerLocked(d time.Duration) {
+	lgs.gameDeadline = time.Now().Add(d)
+	lgs.gameTimer = time.AfterFunc(d, lgs.expireSelfPacedGame)
+	lgs.startTickTimerLocked()
+}
+
+func (lgs *LiveGameStore) expireSelfPacedGame() {
+	lgs.mutex.Lock()
+	if lgs.gameStatus != GameStatusRunning || lgs.paused {
+		lgs.mutex.Unlock()
+		return
+	}
+	lgs.endGameLocked()
+	lgs.mutex.Unlock()
+
+	lgs.broadcastAdvance(true)
+}
+
+// Records and grades a player's answer to the question they are on, then sends them their next question.
+// The game ends once every player has answered every question.
+func (lgs *LiveGameStore) submitSelfPacedAnswer(playerId uuid.UUID, questionNumber int, answer string) (LiveAnswer, error) {
+	lgs.mutex.Lock()
+	if lgs.paused {
+		lgs.mutex.Unlock()
+		return LiveAnswer{}, &types.ErrGamePaused{}
+	}
+	index := lgs.playerIndexLocked(playerId)
+	if index < 0 {
+		lgs.mutex.Unlock()
+		return LiveAnswer{}, &types.ErrPlayerNotFound{PlayerId: playerId}
+	}
+	cursor := lgs.cursors[playerId]
+	if questionNumber < cursor {
+		lgs.mutex.Unlock()
+		return LiveAnswer{}, &types.ErrAnswerAlreadySubmitted{PlayerId: playerId, QuestionNumber: questionNumber}
+	}
+	if lgs.deadlinePassedLocked(time.Now()) {
+		lgs.mutex.Unlock()
+		return LiveAnswer{}, &types.ErrAnswerTooLate{QuestionNumber: questionNumber, Deadline: lgs.gameDeadline}
+	}
+	if lgs.gameStatus != GameStatusRunning || questionNumber != cursor || cursor >= len(lgs.questionIds) {
+		lgs.mutex.Unlock()
+		return LiveAnswer{}, &types.ErrQuestionNotOpen{QuestionNumber: questionNumber}
+	}
+	problem, err := lgs.questionStore.GetProblemById(lgs.questionIds[cursor])
+	if err != nil {
+		lgs.mutex.Unlock()
+		return LiveAnswer{}, fmt.Errorf("QuestionId does not exist %v", lgs.questionIds[cursor])
+	}
+	if err := models.ValidateAnswerShape(problem.Type, answer); err != nil {
+		lgs.mutex.Unlock()
+		return LiveAnswer{}, &types.ErrInvalidAnswer{QuestionNumber: cursor, Reason: err.Error()}
+	}
+
+	now := time.Now()
+	credit := problem.Grade(answer, lgs.creditMode)
+	liveAnswer := LiveAnswer{
+		PlayerId:     playerId,
+		PlayerName:   lgs.players[index].Name,
+		Answer:       answer,
+		Correct:      credit == 1,
+		Score:        credit,
+		SubmittedAt:  now,
+		ResponseTime: now.Sub(lgs.cursorStartedAtLocked(playerId)),
+	}
+	if lgs.answers[cursor] == nil {
+		lgs.answers[cursor] = make(map[uuid.UUID]LiveAnswer)
+	}
+	lgs.answers[cursor][playerId] = liveAnswer
+	lgs.scoreAnswerLocked(index, liveAnswer, true)
+	lgs.rankPlayersLocked()
+	lgs.cursors[playerId] = cursor + 1
+	lgs.cursorStartedAt[playerId] = now
+
+	var next *models.MessageTypeQuestionContent
+	if cursor+1 < len(lgs.questionIds) {
+		if question, err := lgs.questionContentLocked(cursor+1, playerId); err == nil {
+			next = &question
+		}
+	}
+	done := lgs.allPlayersFinishedLocked()
+	if done {
+		lgs.endGameLocked()
+	}
+	progress := lgs.progressContentLocked()
+	lgs.mutex.Unlock()
+
+	if next != nil {
+		lgs.sendToPlayer(playerId, models.CreateMessage(models.MessageTypeNextQuestion, "System", *next))
+	}
+	lgs.sendProgress(progress)
+	if done {
+		lgs.broadcastAdvance(true)
+	}
+	return liveAnswer, nil
+}
+
+// Players without a start time of their own are timed from the start of the game. Caller must hold the mutex.
+func (lgs *LiveGameStore) cursorStartedAtLocked(playerId uuid.UUID) time.Time {
+	startedAt, ok := lgs.cursorStartedAt[playerId]
+	if !ok {
+		startedAt = lgs.questionStartedAt
+	}
+	return startedAt
+}
+
+// Reports whether every connected player has answered every question. Caller must hold the mutex.
+func (lgs *LiveGameStore) allPlayersFinishedLocked() bool {
+	playing := 0
+	for _, p := range lgs.players {
+		if !p.Connected {
+			continue
+		}
+		playing++
+		if lgs.cursors[p.Id] < len(lgs.questionIds) {
+			return false
+		}
+	}
+	return playing > 0
+}
+
+// Gets the progress grid of a self-paced game
+func (lgs *LiveGameStore) GetProgress() (models.ProgressContent, error) {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	if lgs.gameMode != GameModeSelfPaced {
+		return models.ProgressContent{}, fmt.Errorf("Progress is only kept in a self-paced game. Game mode: %v", lgs.gameMode)
+	}
+	return lgs.progressContentLocked(), nil
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) progressContentLocked() models.ProgressContent {
+	content := models.ProgressContent{
+		QuestionCount: len(lgs.questionIds),
+		Deadline:      lgs.gameDeadline,
+		Players:       make([]models.PlayerProgress, len(lgs.players)),
+	}
+	for i, p := range lgs.players {
+		cursor := lgs.cursors[p.Id]
+		results := make([]bool, cursor)
+		for q := range cursor {
+			results[q] = lgs.answers[q][p.Id].Correct
+		}
+		content.Players[i] = models.PlayerProgress{
+			Name:           p.Name,
+			QuestionNumber: cursor,
+			Results:        results,
+			Score:          p.Score,
+			Finished:       cursor >= len(lgs.questionIds),
+		}
+	}
+	return content
+}
+
+func (lgs *LiveGameStore) sendProgress(progress models.ProgressContent) {
+	msg := models.CreateMessage(models.MessageTypeProgress, "System", progress)
+	lgs.sendToHost(msg)
+	lgs.sendToSpectators(msg)
+}
@@ -0,0 +1,119 @@
+package livegame_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+)
+
+func TestSelfPaced(t *testing.T) {
+	selfPaced := livegame.GameOptions{Mode: livegame.GameModeSelfPaced}
+	timed := livegame.GameOptions{Mode: livegame.GameModeSelfPaced, Duration: 1}
+
+	t.Run("Players advance independently", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createGameWithProblems(t, notifier, selfPaced, liveProblems, "Alex", "Bob")
+
+		answer, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertTrue(t, answer.Correct)
+
+		// Alex moved on alone
+		questions := notifier.playerMessagesOfType(ids[0], models.MessageTypeNextQuestion)
+		testutils.AssertEqual(t, len(questions), 1)
+		testutils.AssertEqual(t, questions[0].Content.(models.MessageTypeQuestionContent).QuestionNumber, 1)
+		testutils.AssertEqual(t, len(notifier.playerMessagesOfType(ids[1], models.MessageTypeNextQuestion)), 0)
+
+		_, err = store.SubmitAnswer(ids[0], 0, "3")
+		var alreadyErr *types.ErrAnswerAlreadySubmitted
+		testutils.AssertTrue(t, errors.As(err, &alreadyErr))
+		_, err = store.SubmitAnswer(ids[1], 1, "4")
+		var notOpenErr *types.ErrQuestionNotOpen
+		testutils.AssertTrue(t, errors.As(err, &notOpenErr))
+
+		// Host controls that move everyone at once do not apply
+		testutils.AssertHasError(t, store.CloseQuestion())
+		testutils.AssertHasError(t, store.SkipQuestion())
+
+		state, err := store.GetPlayerState(ids[0])
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, state.QuestionNumber, 1)
+		testutils.AssertEqual(t, state.Question.Question, "2*2")
+
+		_, err = store.SubmitAnswer(ids[0], 1, "5")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusRunning)
+
+		_, err = store.SubmitAnswer(ids[1], 0, "3")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(ids[1], 1, "4")
+		testutils.AssertNoError(t, err)
+
+		// The game ends once everyone has finished
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+		testutils.AssertEqual(t, len(notifier.messagesOfType(models.MessageTypePodium)), 1)
+		bob, _ := store.GetPlayerById(ids[1])
+		testutils.AssertEqual(t, bob.CorrectCount, 2)
+		testutils.AssertEqual(t, bob.Rank, 1)
+	})
+
+	t.Run("Host sees a progress grid", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createGameWithProblems(t, notifier, selfPaced, liveProblems, "Alex", "Bob")
+		testutils.AssertEqual(t, len(notifier.hostMessagesOfType(models.MessageTypeProgress)), 1)
+
+		_, err := store.SubmitAnswer(ids[0], 0, "7")
+		testutils.AssertNoError(t, err)
+
+		grids := notifier.hostMessagesOfType(models.MessageTypeProgress)
+		testutils.AssertEqual(t, len(grids), 2)
+		progress := grids[1].Content.(models.ProgressContent)
+		testutils.AssertEqual(t, progress.QuestionCount, 2)
+		testutils.AssertEqual(t, progress.Players[0].QuestionNumber, 1)
+		testutils.AssertTrue(t, slices.Equal(progress.Players[0].Results, []bool{false}))
+		testutils.AssertEqual(t, progress.Players[1].QuestionNumber, 0)
+		testutils.AssertEqual(t, len(notifier.spectatorMessagesOfType(models.MessageTypeProgress)), 2)
+
+		got, err := store.GetProgress()
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, got.Players[0].QuestionNumber, 1)
+	})
+
+	t.Run("Game ends at the deadline", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, ids := createGameWithProblems(t, notifier, timed, liveProblems, "Alex", "Bob")
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+
+		time.Sleep(1200 * time.Millisecond)
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusDone)
+		_, err = store.SubmitAnswer(ids[1], 0, "3")
+		testutils.AssertHasError(t, err)
+	})
+
+	t.Run("Pausing stops the deadline", func(t *testing.T) {
+		store, ids := createGameWithProblems(t, nil, timed, liveProblems, "Alex")
+		testutils.AssertNoError(t, store.PauseGame())
+		_, err := store.SubmitAnswer(ids[0], 0, "3")
+		var pausedErr *types.ErrGamePaused
+		testutils.AssertTrue(t, errors.As(err, &pausedErr))
+
+		time.Sleep(1200 * time.Millisecond)
+		testutils.AssertEqual(t, store.GetGameStatus(), livegame.GameStatusPaused)
+		testutils.AssertNoError(t, store.ResumeGame())
+		_, err = store.SubmitAnswer(ids[0], 0, "3")
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("Progress is only kept in self-paced games", func(t *testing.T) {
+		store, _ := createRunningGame(t, "Alex")
+		_, err := store.GetProgress()
+		testutils.AssertHasError(t, err)
+	})
+}
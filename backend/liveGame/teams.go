@@ -0,0 +1,174 @@
+package livegame
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+// How a team's score is worked out from its members' scores
+type TeamScoring string
+
+const (
+	TeamScoringSum     TeamScoring = "sum"
+	TeamScoringAverage TeamScoring = "average"
+)
+
+// Fewest teams a team game can have
+const MinTeams = 2
+
+func (ts TeamScoring) String() string {
+	return string(ts)
+}
+
+func (ts TeamScoring) IsValid() bool {
+	return ts == TeamScoringSum || ts == TeamScoringAverage
+}
+
+func ParseTeamScoring(s string) (TeamScoring, error) {
+	ts := TeamScoring(strings.ToLower(s))
+	if !ts.IsValid() {
+		return "", fmt.Errorf("invalid team scoring: %s", s)
+	}
+	return ts, nil
+}
+
+// Checks the teams chosen for a game. No teams means the game is played individually.
+func validateTeams(teams []string) error {
+	if len(teams) == 0 {
+		return nil
+	}
+	if len(teams) < MinTeams {
+		return fmt.Errorf("A team game needs at least %d teams", MinTeams)
+	}
+	for i, team := range teams {
+		if strings.TrimSpace(team) == "" {
+			return fmt.Errorf("Team name cannot be empty")
+		}
+		for _, other := range teams[:i] {
+			if strings.EqualFold(team, other) {
+				return fmt.Errorf("Duplicate team name: %v", team)
+			}
+		}
+	}
+	return nil
+}
+
+func (lgs *LiveGameStore) IsTeamMode() bool {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return len(lgs.teams) > 0
+}
+
+// Finds the team a joining player is placed in. An empty choice picks the team with the fewest players.
+// Caller must hold the mutex.
+func (lgs *LiveGameStore) chooseTeamLocked(choice string) (string, error) {
+	if len(lgs.teams) == 0 {
+		if choice != "" {
+			return "", &types.ErrTeamNotFound{Team: choice}
+		}
+		return "", nil
+	}
+	if choice != "" {
+		index := slices.IndexFunc(lgs.teams, func(team string) bool {
+			return strings.EqualFold(team, choice)
+		})
+		if index < 0 {
+			return "", &types.ErrTeamNotFound{Team: choice}
+		}
+		return lgs.teams[index], nil
+	}
+
+	counts := make(map[string]int, len(lgs.teams))
+	for _, p := range lgs.players {
+		counts[p.Team]++
+	}
+	smallest := lgs.teams[0]
+	for _, team := range lgs.teams[1:] {
+		if counts[team] < counts[smallest] {
+			smallest = team
+		}
+	}
+	return smallest, nil
+}
+
+// Gets the teams ordered by score, highest first, with their members
+func (lgs *LiveGameStore) GetTeams() []models.TeamEntry {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.teamEntriesLocked()
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) teamEntriesLocked() []models.TeamEntry {
+	entries := make([]models.TeamEntry, len(lgs.teams))
+	for i, team := range lgs.teams {
+		entries[i] = models.TeamEntry{Name: team, Members: []string{}}
+	}
+	for _, p := range lgs.sortedPlayersLocked() {
+		index := slices.Index(lgs.teams, p.Team)
+		if index < 0 {
+			continue
+		}
+		entries[index].Members = append(entries[index].Members, p.Name)
+		entries[index].Score += p.Score
+	}
+	if lgs.teamScoring == TeamScoringAverage {
+		for i := range entries {
+			if len(entries[i].Members) > 0 {
+				entries[i].Score /= len(entries[i].Members)
+			}
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b models.TeamEntry) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries
+}
+
+// Gets the ids of everyone on the player's team, including the player
+func (lgs *LiveGameStore) GetTeammateIds(playerId uuid.UUID) (string, []uuid.UUID, error) {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+
+	index := lgs.playerIndexLocked(playerId)
+	if index < 0 {
+		return "", nil, &types.ErrPlayerNotFound{PlayerId: playerId}
+	}
+	team := lgs.players[index].Team
+	if team == "" {
+		return "", nil, fmt.Errorf("Player is not on a team")
+	}
+	ids := []uuid.UUID{}
+	for _, p := range lgs.players {
+		if p.Team == team {
+			ids = append(ids, p.Id)
+		}
+	}
+	return team, ids, nil
+}
+
+// Broadcasts the teams and their scores. Does nothing if the game is not played in teams.
+func (lgs *LiveGameStore) BroadcastTeamList() {
+	lgs.mutex.RLock()
+	if len(lgs.teams) == 0 {
+		lgs.mutex.RUnlock()
+		return
+	}
+	content := models.TeamListContent{Scoring: string(lgs.teamScoring), Teams: lgs.teamEntriesLocked()}
+	lgs.mutex.RUnlock()
+
+	lgs.broadcast(models.CreateMessage(models.MessageTypeTeamList, "System", content))
+}
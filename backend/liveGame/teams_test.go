@@ -0,0 +1,148 @@
+package livegame_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/adettinger/go-quizgame/webserver"
+	"github.com/google/uuid"
+)
+
+func TestTeamOptions(t *testing.T) {
+	cases := []struct {
+		name    string
+		options livegame.GameOptions
+		wantErr bool
+	}{
+		{"No teams", livegame.GameOptions{}, false},
+		{"Two teams", livegame.GameOptions{Teams: []string{"Red", "Blue"}}, false},
+		{"Average scoring", livegame.GameOptions{Teams: []string{"Red", "Blue"}, TeamScoring: livegame.TeamScoringAverage}, false},
+		{"One team", livegame.GameOptions{Teams: []string{"Red"}}, true},
+		{"Duplicate teams", livegame.GameOptions{Teams: []string{"Red", "red"}}, true},
+		{"Empty team name", livegame.GameOptions{Teams: []string{"Red", " "}}, true},
+		{"Invalid team scoring", livegame.GameOptions{Teams: []string{"Red", "Blue"}, TeamScoring: "median"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := livegame.NewLiveGameStore(&webserver.QuestionStore{})
+			err := store.SetupGameOptions(tc.options)
+			if tc.wantErr {
+				testutils.AssertHasError(t, err)
+			} else {
+				testutils.AssertNoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseTeamScoring(t *testing.T) {
+	ts, err := livegame.ParseTeamScoring("Average")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, ts, livegame.TeamScoringAverage)
+	_, err = livegame.ParseTeamScoring("median")
+	testutils.AssertHasError(t, err)
+}
+
+func TestTeamMembership(t *testing.T) {
+	var teamErr *types.ErrTeamNotFound
+
+	t.Run("Players are auto-assigned to the smallest team", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30, Teams: []string{"Red", "Blue"}})
+		testutils.AssertTrue(t, store.IsTeamMode())
+
+		for _, name := range []string{"Alex", "Bob", "Cam"} {
+			_, err := store.AddPlayer(name)
+			testutils.AssertNoError(t, err)
+		}
+		alex, _ := store.GetPlayerByName("Alex")
+		bob, _ := store.GetPlayerByName("Bob")
+		cam, _ := store.GetPlayerByName("Cam")
+		testutils.AssertEqual(t, alex.Team, "Red")
+		testutils.AssertEqual(t, bob.Team, "Blue")
+		testutils.AssertEqual(t, cam.Team, "Red")
+	})
+
+	t.Run("Players may pick their team", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30, Teams: []string{"Red", "Blue"}})
+
+		id, err := store.AddPlayerToTeam("Alex", "blue")
+		testutils.AssertNoError(t, err)
+		player, _ := store.GetPlayerById(id)
+		testutils.AssertEqual(t, player.Team, "Blue")
+
+		_, err = store.AddPlayerToTeam("Bob", "Green")
+		testutils.AssertTrue(t, errors.As(err, &teamErr))
+		testutils.AssertFalse(t, store.PlayerExistsByName("Bob"))
+	})
+
+	t.Run("Individual games have no teams", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30}, "Alex")
+		testutils.AssertFalse(t, store.IsTeamMode())
+		testutils.AssertEqual(t, len(store.GetTeams()), 0)
+
+		_, err := store.AddPlayerToTeam("Bob", "Red")
+		testutils.AssertTrue(t, errors.As(err, &teamErr))
+
+		id, _ := store.AddPlayer("Cam")
+		_, _, err = store.GetTeammateIds(id)
+		testutils.AssertHasError(t, err)
+	})
+
+	t.Run("Teammates are the players on the same team", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30, Teams: []string{"Red", "Blue"}}, "Alex", "Bob", "Cam")
+		alex, _ := store.GetPlayerByName("Alex")
+		cam, _ := store.GetPlayerByName("Cam")
+
+		team, ids, err := store.GetTeammateIds(alex.Id)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, team, "Red")
+		testutils.AssertTrue(t, slices.Equal(ids, []uuid.UUID{alex.Id, cam.Id}))
+	})
+}
+
+func TestTeamScores(t *testing.T) {
+	// Red is Alex and Cam, Blue is Bob
+	play := func(t *testing.T, scoring livegame.TeamScoring) (*livegame.LiveGameStore, *recordingNotifier) {
+		t.Helper()
+		notifier := &recordingNotifier{}
+		store := setupGameWithNotifier(t, notifier, livegame.GameOptions{TimeLimit: 30, Teams: []string{"Red", "Blue"}, TeamScoring: scoring}, "Alex", "Bob", "Cam")
+		testutils.AssertNoError(t, store.StartGame())
+		for name, answer := range map[string]string{"Alex": "3", "Bob": "3", "Cam": "0"} {
+			player, _ := store.GetPlayerByName(name)
+			_, err := store.SubmitAnswer(player.Id, 0, answer)
+			testutils.AssertNoError(t, err)
+		}
+		return store, notifier
+	}
+
+	t.Run("Sum adds member scores", func(t *testing.T) {
+		store, notifier := play(t, livegame.TeamScoringSum)
+		teams := store.GetTeams()
+		testutils.AssertEqual(t, teams[0].Score, livegame.QuestionPoints)
+		testutils.AssertEqual(t, teams[0].Rank, 1)
+		testutils.AssertEqual(t, teams[1].Score, livegame.QuestionPoints)
+		testutils.AssertEqual(t, teams[1].Rank, 1)
+
+		lists := notifier.messagesOfType(models.MessageTypeTeamList)
+		testutils.AssertEqual(t, len(lists), 1)
+		content := lists[0].Content.(models.TeamListContent)
+		testutils.AssertEqual(t, content.Scoring, "sum")
+		testutils.AssertEqual(t, len(content.Teams), 2)
+	})
+
+	t.Run("Average divides by team size", func(t *testing.T) {
+		store, _ := play(t, livegame.TeamScoringAverage)
+		teams := store.GetTeams()
+		testutils.AssertEqual(t, teams[0].Name, "Blue")
+		testutils.AssertEqual(t, teams[0].Score, livegame.QuestionPoints)
+		testutils.AssertEqual(t, teams[1].Name, "Red")
+		testutils.AssertEqual(t, teams[1].Score, livegame.QuestionPoints/2)
+		testutils.AssertEqual(t, teams[1].Rank, 2)
+		testutils.AssertEqual(t, len(teams[1].Members), 2)
+	})
+}
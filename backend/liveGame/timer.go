@@ -0,0 +1,109 @@
+package livegame
+
+import (
+	"math"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+)
+
+// How often the time left on the open question is sent to everyone
+const TimerTickInterval = time.Second
+
+func (lgs *LiveGameStore) SetTimerTickInterval(d time.Duration) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	lgs.timerTickInterval = d
+}
+
+// Gets the timer of the open question, or of the whole game when self-paced.
+// Returns false when nothing is being timed.
+func (lgs *LiveGameStore) GetTimer() (models.TimerContent, bool) {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.timerContentLocked()
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) timerContentLocked() (models.TimerContent, bool) {
+	if lgs.gameStatus != GameStatusRunning && lgs.gameStatus != GameStatusPaused {
+		return models.TimerContent{}, false
+	}
+	if lgs.questionStatus != QuestionStatusGathering {
+		return models.TimerContent{}, false
+	}
+	content := models.TimerContent{QuestionNumber: lgs.currentQuestion, Paused: lgs.paused}
+	deadline, remaining := lgs.questionDeadline, lgs.questionRemaining
+	if lgs.gameMode == GameModeSelfPaced {
+		content.QuestionNumber = 0
+		content.WholeGame = true
+		content.StartedAt = lgs.startedAt
+		deadline, remaining = lgs.gameDeadline, lgs.gameRemaining
+	} else {
+		content.StartedAt = lgs.questionStartedAt
+	}
+	if !lgs.paused {
+		content.Deadline = &deadline
+		remaining = max(time.Until(deadline), 0)
+	}
+	content.RemainingSeconds = int(math.Ceil(remaining.Seconds()))
+	return content, true
+}
+
+// Reports whether the time for answering the current question, or the whole game when self-paced, is up.
+// Caller must hold the mutex.
+func (lgs *LiveGameStore) deadlinePassedLocked(now time.Time) bool {
+	deadline := lgs.questionDeadline
+	if lgs.gameMode == GameModeSelfPaced {
+		deadline = lgs.gameDeadline
+	}
+	return !deadline.IsZero() && now.After(deadline)
+}
+
+// Sends the time left every tick until the timer stops. Caller must hold the mutex.
+func (lgs *LiveGameStore) startTickTimerLocked() {
+	lgs.stopTickTimerLocked()
+	generation := lgs.tickGeneration
+	lgs.tickTimer = time.AfterFunc(lgs.timerTickInterval, func() { lgs.tick(generation) })
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) stopTickTimerLocked() {
+	// A tick already waiting on the mutex sees the generation has moved on
+	lgs.tickGeneration++
+	if lgs.tickTimer != nil {
+		lgs.tickTimer.Stop()
+		lgs.tickTimer = nil
+	}
+}
+
+func (lgs *LiveGameStore) tick(generation int) {
+	lgs.mutex.Lock()
+	if generation != lgs.tickGeneration {
+		lgs.mutex.Unlock()
+		return
+	}
+	content, ok := lgs.timerContentLocked()
+	if !ok || lgs.paused {
+		lgs.tickTimer = nil
+		lgs.mutex.Unlock()
+		return
+	}
+	if content.RemainingSeconds > 0 {
+		lgs.tickTimer = time.AfterFunc(lgs.timerTickInterval, func() { lgs.tick(generation) })
+	} else {
+		lgs.tickTimer = nil
+	}
+	lgs.mutex.Unlock()
+
+	lgs.broadcast(models.CreateMessage(models.MessageTypeTimer, "System", content))
+}
+
+// Sends the timer of the open question to everyone, if there is one
+func (lgs *LiveGameStore) broadcastTimer() {
+	content, ok := lgs.GetTimer()
+	if !ok {
+		return
+	}
+	lgs.broadcast(models.CreateMessage(models.MessageTypeTimer, "System", content))
+}
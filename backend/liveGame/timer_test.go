@@ -0,0 +1,98 @@
+package livegame_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/adettinger/go-quizgame/types"
+)
+
+func timerContents(notifier *recordingNotifier) []models.TimerContent {
+	contents := []models.TimerContent{}
+	for _, m := range notifier.messagesOfType(models.MessageTypeTimer) {
+		contents = append(contents, m.Content.(models.TimerContent))
+	}
+	return contents
+}
+
+func TestQuestionTimer(t *testing.T) {
+	t.Run("Ticks until time is up", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store := setupGameWithNotifier(t, notifier, livegame.GameOptions{TimeLimit: 1}, "Alex", "Bob")
+		store.SetTimerTickInterval(200 * time.Millisecond)
+		testutils.AssertNoError(t, store.StartGame())
+
+		start := timerContents(notifier)
+		testutils.AssertEqual(t, len(start), 1)
+		testutils.AssertEqual(t, start[0].QuestionNumber, 0)
+		testutils.AssertEqual(t, start[0].RemainingSeconds, 1)
+		testutils.AssertTrue(t, start[0].Deadline != nil)
+		testutils.AssertEqual(t, start[0].Deadline.Sub(start[0].StartedAt).Round(time.Second), time.Second)
+
+		waitForQuestionStatus(t, store, livegame.QuestionStatusResults, 2*time.Second)
+		time.Sleep(300 * time.Millisecond)
+		timers := timerContents(notifier)
+		testutils.AssertTrue(t, len(timers) >= 4)
+		for i, timer := range timers {
+			testutils.AssertTrue(t, timer.Deadline.Equal(*start[0].Deadline))
+			if i > 0 {
+				testutils.AssertTrue(t, timer.RemainingSeconds <= timers[i-1].RemainingSeconds)
+			}
+		}
+
+		// Ticks stop once the question closes
+		time.Sleep(300 * time.Millisecond)
+		testutils.AssertEqual(t, len(timerContents(notifier)), len(timers))
+	})
+
+	t.Run("Answers after the deadline are refused", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 1}, "Alex", "Bob")
+		testutils.AssertNoError(t, store.StartGame())
+		alex, _ := store.GetPlayerByName("Alex")
+		waitForQuestionStatus(t, store, livegame.QuestionStatusResults, 2*time.Second)
+
+		_, err := store.SubmitAnswer(alex.Id, 0, "3")
+		var lateErr *types.ErrAnswerTooLate
+		testutils.AssertTrue(t, errors.As(err, &lateErr))
+		testutils.AssertEqual(t, lateErr.QuestionNumber, 0)
+		testutils.AssertEqual(t, len(store.GetAnswers(0)), 0)
+	})
+
+	t.Run("Pause freezes the timer", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		store, _ := createRunningGameWithNotifier(t, notifier, "Alex")
+		testutils.AssertNoError(t, store.PauseGame())
+
+		timers := timerContents(notifier)
+		testutils.AssertEqual(t, len(timers), 2)
+		testutils.AssertTrue(t, timers[1].Paused)
+		testutils.AssertTrue(t, timers[1].Deadline == nil)
+		testutils.AssertEqual(t, timers[1].RemainingSeconds, 30)
+
+		testutils.AssertNoError(t, store.ResumeGame())
+		timers = timerContents(notifier)
+		testutils.AssertEqual(t, len(timers), 3)
+		testutils.AssertFalse(t, timers[2].Paused)
+		testutils.AssertTrue(t, timers[2].Deadline != nil)
+		testutils.AssertTrue(t, timers[2].Deadline.After(*timers[0].Deadline))
+	})
+
+	t.Run("Self-paced games time the whole game", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		createGameWithProblems(t, notifier, livegame.GameOptions{Mode: livegame.GameModeSelfPaced, Duration: 60}, liveProblems, "Alex")
+		timers := timerContents(notifier)
+		testutils.AssertEqual(t, len(timers), 1)
+		testutils.AssertTrue(t, timers[0].WholeGame)
+		testutils.AssertEqual(t, timers[0].RemainingSeconds, 60)
+	})
+
+	t.Run("No timer before the game starts", func(t *testing.T) {
+		store := setupGameWithNotifier(t, nil, livegame.GameOptions{TimeLimit: 30}, "Alex")
+		_, ok := store.GetTimer()
+		testutils.AssertFalse(t, ok)
+	})
+}
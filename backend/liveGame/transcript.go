@@ -0,0 +1,141 @@
+package livegame
+
+import (
+	"log"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/google/uuid"
+)
+
+// A player who is no longer in the game, kept for the transcript
+type departedPlayer struct {
+	LivePlayer
+	Departure models.PlayerDeparture
+}
+
+// Sets where the transcript of each finished game is kept
+func (lgs *LiveGameStore) SetArchive(archive *Archive) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	lgs.archive = archive
+}
+
+// Gets the id the results of the current game are archived under
+func (lgs *LiveGameStore) GetGameId() uuid.UUID {
+	lgs.mutex.RLock()
+	defer lgs.mutex.RUnlock()
+	return lgs.gameId
+}
+
+// Keeps a chat message for the transcript. Team is empty for chat everyone sees.
+func (lgs *LiveGameStore) RecordChat(playerName string, text string, team string) {
+	lgs.mutex.Lock()
+	defer lgs.mutex.Unlock()
+	if lgs.gameStatus == GameStatusNotSetup {
+		return
+	}
+	lgs.chat = append(lgs.chat, models.ChatEntry{
+		PlayerName: playerName,
+		Text:       text,
+		Team:       team,
+		SentAt:     time.Now(),
+	})
+}
+
+// Saves the transcript of a finished game to the archive
+func (lgs *LiveGameStore) archiveGame() {
+	lgs.mutex.RLock()
+	archive := lgs.archive
+	var transcript models.Transcript
+	if archive != nil {
+		transcript = lgs.transcriptLocked()
+	}
+	lgs.mutex.RUnlock()
+
+	if archive == nil {
+		return
+	}
+	if err := archive.Save(transcript); err != nil {
+		log.Printf("Failed to archive game %v: %v", transcript.GameId, err)
+	}
+}
+
+// Caller must hold the mutex
+func (lgs *LiveGameStore) transcriptLocked() models.Transcript {
+	transcript := models.Transcript{
+		GameId:      lgs.gameId,
+		Mode:        lgs.gameMode.String(),
+		ScoringMode: lgs.scoringMode.String(),
+		StartedAt:   lgs.startedAt,
+		EndedAt:     time.Now(),
+		Players:     make([]models.TranscriptPlayer, 0, len(lgs.players)+len(lgs.departed)),
+		Questions:   []models.TranscriptQuestion{},
+		Standings:   toLeaderboardEntries(lgs.sortedPlayersLocked()),
+		Chat:        append([]models.ChatEntry{}, lgs.chat...),
+		HostToken:   lgs.hostToken,
+	}
+	for _, p := range lgs.players {
+		transcript.Players = append(transcript.Players, toTranscriptPlayer(p, ""))
+	}
+	for _, p := range lgs.departed {
+		transcript.Players = append(transcript.Players, toTranscriptPlayer(p.LivePlayer, p.Departure))
+	}
+	if len(lgs.teams) > 0 {
+		transcript.Teams = lgs.teamEntriesLocked()
+	}
+
+	for questionNumber := range lgs.askedQuestionCountLocked() {
+		problem, err := lgs.questionStore.GetProblemById(lgs.questionIds[questionNumber])
+		if err != nil {
+			log.Printf("Transcript: QuestionId does not exist %v", lgs.questionIds[questionNumber])
+			continue
+		}
+		question := models.TranscriptQuestion{
+			QuestionNumber: questionNumber,
+			QuestionId:     problem.Id,
+			Type:           problem.Type.String(),
+			Question:       problem.Question,
+			Choices:        problem.DisplayChoices(),
+			Answers:        []models.TranscriptAnswer{},
+		}
+		for _, a := range lgs.sortedAnswersLocked(questionNumber) {
+			question.Answers = append(question.Answers, models.TranscriptAnswer{
+				PlayerId:       a.PlayerId,
+				PlayerName:     a.PlayerName,
+				Answer:         a.Answer,
+				Correct:        a.Correct,
+				Score:          a.Score,
+				SubmittedAt:    a.SubmittedAt,
+				ResponseTimeMs: a.ResponseTime.Milliseconds(),
+			})
+		}
+		question.Results, _ = lgs.questionResultsLocked(questionNumber)
+		transcript.Questions = append(transcript.Questions, question)
+	}
+	return transcript
+}
+
+func toTranscriptPlayer(p LivePlayer, departure models.PlayerDeparture) models.TranscriptPlayer {
+	return models.TranscriptPlayer{
+		Id:           p.Id,
+		Name:         p.Name,
+		Team:         p.Team,
+		Score:        p.Score,
+		CorrectCount: p.CorrectCount,
+		Departure:    departure,
+	}
+}
+
+// Counts the questions that were put to players before the game ended. Caller must hold the mutex.
+func (lgs *LiveGameStore) askedQuestionCountLocked() int {
+	if lgs.gameMode != GameModeSelfPaced {
+		return min(lgs.currentQuestion+1, len(lgs.questionIds))
+	}
+	// Everyone starts on the first question
+	asked := 1
+	for _, cursor := range lgs.cursors {
+		asked = max(asked, cursor+1)
+	}
+	return min(asked, len(lgs.questionIds))
+}
@@ -0,0 +1,121 @@
+package livegame_test
+
+import (
+	"testing"
+	"time"
+
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+	"github.com/google/uuid"
+)
+
+func TestTranscript(t *testing.T) {
+	t.Run("Finished games are archived", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		archive := livegame.NewArchive("")
+		store := setupGameWithNotifier(t, notifier, livegame.GameOptions{TimeLimit: 30}, "Alex", "Bob")
+		store.SetArchive(archive)
+		store.RecordChat("Alex", "good luck", "")
+		testutils.AssertNoError(t, store.StartGame())
+		alex, _ := store.GetPlayerByName("Alex")
+		bob, _ := store.GetPlayerByName("Bob")
+
+		_, err := store.SubmitAnswer(bob.Id, 0, "7")
+		testutils.AssertNoError(t, err)
+		_, err = store.SubmitAnswer(alex.Id, 0, "3")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.EndGame())
+
+		// Only the host is told where to find the results
+		statuses := notifier.messagesOfType(models.MessageTypeGameStatus)
+		testutils.AssertEqual(t, statuses[len(statuses)-1].Content.(models.GameStatusContent).GameId, "")
+		hostStatuses := notifier.hostMessagesOfType(models.MessageTypeGameStatus)
+		gameId := store.GetGameId()
+		testutils.AssertEqual(t, hostStatuses[len(hostStatuses)-1].Content.(models.GameStatusContent).GameId, gameId.String())
+
+		transcript, err := archive.Get(gameId)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(transcript.Players), 2)
+		testutils.AssertEqual(t, transcript.Standings[0].Name, "Alex")
+		testutils.AssertEqual(t, transcript.HostToken, store.GetHostToken())
+		testutils.AssertEqual(t, len(transcript.Chat), 1)
+		testutils.AssertEqual(t, transcript.Chat[0].Text, "good luck")
+
+		// The second question was never asked
+		testutils.AssertEqual(t, len(transcript.Questions), 1)
+		question := transcript.Questions[0]
+		testutils.AssertEqual(t, question.Question, "1+2")
+		testutils.AssertEqual(t, len(question.Answers), 2)
+		testutils.AssertEqual(t, question.Answers[0].PlayerName, "Bob")
+		testutils.AssertFalse(t, question.Answers[0].Correct)
+		testutils.AssertEqual(t, question.Results.Answer, "3")
+	})
+
+	t.Run("Players who answer and then leave stay in the transcript", func(t *testing.T) {
+		archive := livegame.NewArchive("")
+		store, ids := createRunningGame(t, "Alex", "Bob", "Cam")
+		store.SetArchive(archive)
+		store.SetReconnectGracePeriod(20 * time.Millisecond)
+		for _, id := range ids {
+			_, err := store.SubmitAnswer(id, 0, "3")
+			testutils.AssertNoError(t, err)
+		}
+
+		_, err := store.KickPlayer("Alex")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.DisconnectPlayer(ids[1]))
+		time.Sleep(100 * time.Millisecond)
+		testutils.AssertFalse(t, store.PlayerExistsByName("Bob"))
+		testutils.AssertNoError(t, store.EndGame())
+
+		transcript, err := archive.Get(store.GetGameId())
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(transcript.Standings), 1)
+		testutils.AssertEqual(t, len(transcript.Players), 3)
+		departures := map[string]models.PlayerDeparture{}
+		for _, p := range transcript.Players {
+			departures[p.Name] = p.Departure
+			testutils.AssertEqual(t, p.CorrectCount, 1)
+			testutils.AssertTrue(t, p.Score > 0)
+		}
+		testutils.AssertEqual(t, departures["Alex"], models.PlayerDepartureKicked)
+		testutils.AssertEqual(t, departures["Bob"], models.PlayerDepartureLeft)
+		testutils.AssertEqual(t, departures["Cam"], models.PlayerDeparture(""))
+		testutils.AssertEqual(t, len(transcript.Questions[0].Answers), 3)
+	})
+
+	t.Run("Players who leave the lobby are not in the transcript", func(t *testing.T) {
+		archive := livegame.NewArchive("")
+		store := setupGameWithNotifier(t, &recordingNotifier{}, livegame.GameOptions{TimeLimit: 30}, "Alex", "Bob")
+		store.SetArchive(archive)
+		_, err := store.KickPlayer("Bob")
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, store.StartGame())
+		testutils.AssertNoError(t, store.EndGame())
+
+		transcript, err := archive.Get(store.GetGameId())
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(transcript.Players), 1)
+	})
+
+	t.Run("Each game gets its own transcript", func(t *testing.T) {
+		archive := livegame.NewArchive("")
+		store, _ := createRunningGame(t, "Alex")
+		store.SetArchive(archive)
+		store.RecordChat("Alex", "first game", "")
+		testutils.AssertNoError(t, store.EndGame())
+		firstId := store.GetGameId()
+
+		testutils.AssertNoError(t, store.SetupGameOptions(livegame.GameOptions{TimeLimit: 30, QuestionIds: []uuid.UUID{liveProblems[0].Id}}))
+		testutils.AssertNoError(t, store.StartGame())
+		testutils.AssertNoError(t, store.EndGame())
+		testutils.AssertNotEqual(t, store.GetGameId(), firstId)
+
+		second, err := archive.Get(store.GetGameId())
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(second.Chat), 0)
+		_, err = archive.Get(firstId)
+		testutils.AssertNoError(t, err)
+	})
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// How an answer that is partly right is graded
+type CreditMode string
+
+const (
+	CreditModeAllOrNothing CreditMode = "all_or_nothing" // Full credit only for an answer that is wholly right
+	// Credit for each right choice picked, less one for each wrong choice picked, never below 0.
+	// Ordering and matching answers earn credit for each item in the right place or paired with its match.
+	CreditModeProportional CreditMode = "proportional"
+)
+
+func (cm CreditMode) String() string {
+	return string(cm)
+}
+
+func (cm CreditMode) IsValid() bool {
+	switch cm {
+	case CreditModeAllOrNothing, CreditModeProportional:
+		return true
+	}
+	return false
+}
+
+func ParseCreditMode(s string) (CreditMode, error) {
+	cm := CreditMode(strings.ToLower(s))
+	if !cm.IsValid() {
+		return "", fmt.Errorf("invalid credit mode: %s", s)
+	}
+	return cm, nil
+}
+
+// Parses the choices picked for a multi-select problem, or the sequence given for an ordering problem,
+// written as a JSON array such as ["Red","Blue"].
+func ParseSelection(s string) ([]string, error) {
+	var selection []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(s)), &selection); err != nil {
+		return nil, fmt.Errorf("Selection must be a JSON array of choices. %v", err)
+	}
+	return selection, nil
+}
+
+// Writes choices the way ParseSelection reads them
+func FormatSelection(selection []string) string {
+	return serializeArray(selection)
+}
+
+// Gives the fraction of full credit an answer earns, from 0 to 1.
+// Only multi-select, ordering and matching problems can earn partial credit.
+func (p Problem) Grade(answer string, mode CreditMode) float64 {
+	switch p.Type {
+	case ProblemTypeNumeric:
+		numeric, err := ParseNumericAnswer(p.Answer)
+		return creditFor(err == nil && numeric.Matches(answer))
+	case ProblemTypeMulti:
+		return p.gradeSelection(answer, mode)
+	case ProblemTypeOrdering:
+		return p.gradeOrder(answer, mode)
+	case ProblemTypeMatching:
+		return p.gradeMatching(answer, mode)
+	case ProblemTypeTrueFalse:
+		correct, err := ParseTrueFalse(p.Answer)
+		given, givenErr := ParseTrueFalse(answer)
+		return creditFor(err == nil && givenErr == nil && given == correct)
+	}
+	return creditFor(p.acceptsAnswer(strings.TrimSpace(answer)))
+}
+
+// Reports whether answer is the problem's answer or one of its aliases
+func (p Problem) acceptsAnswer(answer string) bool {
+	if strings.EqualFold(answer, p.Answer) {
+		return true
+	}
+	return slices.ContainsFunc(p.Aliases, func(a string) bool { return strings.EqualFold(answer, strings.TrimSpace(a)) })
+}
+
+func (p Problem) gradeSelection(answer string, mode CreditMode) float64 {
+	correct, err := ParseSelection(p.Answer)
+	if err != nil || len(correct) == 0 {
+		return 0
+	}
+	selection, err := ParseSelection(answer)
+	if err != nil {
+		// A lone choice that is not written as an array
+		selection = []string{answer}
+	}
+	isCorrect := make(map[string]bool, len(correct))
+	for _, c := range correct {
+		isCorrect[strings.ToLower(strings.TrimSpace(c))] = true
+	}
+	picked := make(map[string]bool, len(selection))
+	right, wrong := 0, 0
+	for _, s := range selection {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if picked[s] {
+			continue
+		}
+		picked[s] = true
+		if isCorrect[s] {
+			right++
+		} else {
+			wrong++
+		}
+	}
+	if mode == CreditModeProportional {
+		return max(0, float64(right-wrong)/float64(len(isCorrect)))
+	}
+	return creditFor(right == len(isCorrect) && wrong == 0)
+}
+
+// Credits each item given in its place in the sequence
+func (p Problem) gradeOrder(answer string, mode CreditMode) float64 {
+	correct, err := ParseSelection(p.Answer)
+	if err != nil || len(correct) == 0 {
+		return 0
+	}
+	order, err := ParseSelection(answer)
+	if err != nil {
+		return 0
+	}
+	right := 0
+	for i := range min(len(order), len(correct)) {
+		if strings.EqualFold(strings.TrimSpace(order[i]), strings.TrimSpace(correct[i])) {
+			right++
+		}
+	}
+	if len(order) != len(correct) && mode == CreditModeAllOrNothing {
+		return 0
+	}
+	return partialCredit(right, len(correct), mode)
+}
+
+// Gives the credit for getting right of total parts of an answer right
+func partialCredit(right int, total int, mode CreditMode) float64 {
+	if mode == CreditModeProportional {
+		return float64(right) / float64(total)
+	}
+	return creditFor(right == total)
+}
+
+func creditFor(correct bool) float64 {
+	if correct {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+package models_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+)
+
+func TestParseCreditMode(t *testing.T) {
+	mode, err := models.ParseCreditMode("Proportional")
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, mode, models.CreditModeProportional)
+	_, err = models.ParseCreditMode("some")
+	testutils.AssertHasError(t, err)
+}
+
+func TestGradeMulti(t *testing.T) {
+	problem := models.Problem{
+		Type:     models.ProblemTypeMulti,
+		Question: "Primary colours",
+		Choices:  []string{"Red", "Green", "Blue", "Yellow"},
+		Answer:   `["red","blue","yellow"]`,
+	}
+	cases := []struct {
+		name         string
+		answer       string
+		allOrNothing float64
+		proportional float64
+	}{
+		{"Exactly right", `["Yellow","Red","Blue"]`, 1, 1},
+		{"Repeats count once", `["red","red","blue","yellow"]`, 1, 1},
+		{"Some right", `["red","blue"]`, 0, 2.0 / 3},
+		{"Wrong pick takes one away", `["red","blue","green"]`, 0, 1.0 / 3},
+		{"Never below nothing", `["green"]`, 0, 0},
+		{"Nothing picked", `[]`, 0, 0},
+		{"Lone choice", "red", 0, 1.0 / 3},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.AssertEqual(t, problem.Grade(tt.answer, models.CreditModeAllOrNothing), tt.allOrNothing)
+			testutils.AssertEqual(t, problem.Grade(tt.answer, models.CreditModeProportional), tt.proportional)
+			testutils.AssertEqual(t, problem.IsCorrect(tt.answer), tt.allOrNothing == 1)
+		})
+	}
+}
+
+func TestGradeOrdering(t *testing.T) {
+	problem := models.Problem{
+		Type:     models.ProblemTypeOrdering,
+		Question: "Earliest first",
+		Choices:  []string{"Magna Carta", "Moon landing", "French Revolution", "Printing press"},
+		Answer:   `["magna carta","printing press","french revolution","moon landing"]`,
+	}
+	cases := []struct {
+		name         string
+		answer       string
+		allOrNothing float64
+		proportional float64
+	}{
+		{"Exactly right", `["Magna Carta","Printing press","French Revolution","Moon landing"]`, 1, 1},
+		{"Two swapped", `["Magna Carta","French Revolution","Printing press","Moon landing"]`, 0, 0.5},
+		{"Reversed", `["Moon landing","French Revolution","Printing press","Magna Carta"]`, 0, 0},
+		{"Too short", `["Magna Carta","Printing press","French Revolution"]`, 0, 0.75},
+		{"Not an array", "magna carta", 0, 0},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.AssertEqual(t, problem.Grade(tt.answer, models.CreditModeAllOrNothing), tt.allOrNothing)
+			testutils.AssertEqual(t, problem.Grade(tt.answer, models.CreditModeProportional), tt.proportional)
+		})
+	}
+}
+
+func TestGradeMatching(t *testing.T) {
+	problem := models.Problem{
+		Type:     models.ProblemTypeMatching,
+		Question: "Match each symbol to its element",
+		Choices:  []string{"Na", "K", "Fe", "Au"},
+		Answer:   `{"Au":"Gold","Fe":"Iron","K":"Potassium","Na":"Sodium"}`,
+	}
+	cases := []struct {
+		name         string
+		answer       string
+		allOrNothing float64
+		proportional float64
+	}{
+		{"Exactly right", `{"na":"sodium","k":"potassium","fe":"iron","au":"gold"}`, 1, 1},
+		{"Two swapped", `{"Na":"Potassium","K":"Sodium","Fe":"Iron","Au":"Gold"}`, 0, 0.5},
+		{"Some left out", `{"Na":"Sodium"}`, 0, 0.25},
+		{"Unknown choice", `{"Ag":"Silver","Na":"Sodium"}`, 0, 0.25},
+		{"Not an object", `["Sodium"]`, 0, 0},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.AssertEqual(t, problem.Grade(tt.answer, models.CreditModeAllOrNothing), tt.allOrNothing)
+			testutils.AssertEqual(t, problem.Grade(tt.answer, models.CreditModeProportional), tt.proportional)
+		})
+	}
+}
+
+func TestMatchTargets(t *testing.T) {
+	problem := models.Problem{
+		Type:    models.ProblemTypeMatching,
+		Choices: []string{"Na", "K", "Fe"},
+		Answer:  `{"Na":"Sodium","K":"potassium","Fe":"Iron"}`,
+	}
+	testutils.AssertTrue(t, slices.Equal(problem.MatchTargets(), []string{"Iron", "potassium", "Sodium"}))
+
+	choice := models.Problem{Type: models.ProblemTypeChoice, Choices: []string{"A", "B"}, Answer: "a"}
+	testutils.AssertEqual(t, len(choice.MatchTargets()), 0)
+}
+
+func TestValidateAnswerShape(t *testing.T) {
+	testutils.AssertNoError(t, models.ValidateAnswerShape(models.ProblemTypeOrdering, `["a","b"]`))
+	testutils.AssertHasError(t, models.ValidateAnswerShape(models.ProblemTypeOrdering, "a, b"))
+	testutils.AssertNoError(t, models.ValidateAnswerShape(models.ProblemTypeMatching, `{"a":"b"}`))
+	testutils.AssertHasError(t, models.ValidateAnswerShape(models.ProblemTypeMatching, `["a","b"]`))
+	testutils.AssertNoError(t, models.ValidateAnswerShape(models.ProblemTypeText, "anything"))
+}
+
+func TestGradeSingleAnswer(t *testing.T) {
+	problem := models.Problem{Type: models.ProblemTypeText, Question: "Capital of France", Answer: "paris"}
+	testutils.AssertEqual(t, problem.Grade("Paris", models.CreditModeProportional), 1.0)
+	testutils.AssertEqual(t, problem.Grade("pari", models.CreditModeProportional), 0.0)
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Parses the pairs of a matching problem, written as a JSON object from each choice to its match
+// such as {"Na":"Sodium","K":"Potassium"}.
+func ParseMatching(s string) (map[string]string, error) {
+	var pairs map[string]string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(s)), &pairs); err != nil {
+		return nil, fmt.Errorf("Pairs must be a JSON object from each choice to its match. %v", err)
+	}
+	return pairs, nil
+}
+
+// Writes pairs the way ParseMatching reads them, with the choices in alphabetical order
+func FormatMatching(pairs map[string]string) string {
+	bytes, err := json.Marshal(pairs)
+	if err != nil {
+		panic(err)
+	}
+	return string(bytes)
+}
+
+// Gets what the choices of a matching problem are paired with, in alphabetical order so the order gives nothing away
+func (p Problem) MatchTargets() []string {
+	if p.Type != ProblemTypeMatching {
+		return nil
+	}
+	pairs, err := ParseMatching(p.Answer)
+	if err != nil {
+		return nil
+	}
+	targets := make([]string, 0, len(pairs))
+	for _, target := range pairs {
+		targets = append(targets, target)
+	}
+	slices.SortFunc(targets, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return targets
+}
+
+// Checks every choice is paired with exactly one match and no match is used twice
+func validateMatching(choices []string, answer string) error {
+	pairs, err := ParseMatching(answer)
+	if err != nil {
+		return err
+	}
+	paired := make(map[string]struct{}, len(pairs))
+	targets := make(map[string]struct{}, len(pairs))
+	for choice, target := range pairs {
+		if !slices.ContainsFunc(choices, func(c string) bool { return strings.EqualFold(c, choice) }) {
+			return fmt.Errorf("Answer %q must be one of the choices", choice)
+		}
+		// Choices are graded ignoring case, so {"Na":…,"na":…} pairs one choice twice
+		if _, exists := paired[strings.ToLower(choice)]; exists {
+			return fmt.Errorf("Choice %q is paired more than once", choice)
+		}
+		paired[strings.ToLower(choice)] = struct{}{}
+		if strings.TrimSpace(target) == "" {
+			return fmt.Errorf("Match for %q cannot be empty string", choice)
+		}
+		if _, exists := targets[strings.ToLower(target)]; exists {
+			return fmt.Errorf("Duplicate match found")
+		}
+		targets[strings.ToLower(target)] = struct{}{}
+	}
+	for _, choice := range choices {
+		if _, ok := paired[strings.ToLower(choice)]; !ok {
+			return fmt.Errorf("Matching problems must pair each of the %d choices, %q has no match", len(choices), choice)
+		}
+	}
+	return nil
+}
+
+// Credits each choice paired with its match. Pairs for choices that are not in the problem earn nothing.
+func (p Problem) gradeMatching(answer string, mode CreditMode) float64 {
+	correct, err := ParseMatching(p.Answer)
+	if err != nil || len(correct) == 0 {
+		return 0
+	}
+	matches := make(map[string]string, len(correct))
+	for choice, target := range correct {
+		matches[strings.ToLower(strings.TrimSpace(choice))] = strings.ToLower(strings.TrimSpace(target))
+	}
+	pairs, err := ParseMatching(answer)
+	if err != nil {
+		return 0
+	}
+	right := 0
+	paired := make(map[string]bool, len(pairs))
+	// Sorted so a choice given twice in different cases is graded the same way every time
+	for _, choice := range slices.Sorted(maps.Keys(pairs)) {
+		key := strings.ToLower(strings.TrimSpace(choice))
+		if paired[key] {
+			continue
+		}
+		paired[key] = true
+		if target, ok := matches[key]; ok && target == strings.ToLower(strings.TrimSpace(pairs[choice])) {
+			right++
+		}
+	}
+	return partialCredit(right, len(matches), mode)
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A decimal, scientific or fractional number such as -3, 0.5, 3e0 or 1/2
+const numberPattern = `[+-]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?`
+
+var leadingNumber = regexp.MustCompile(`^(` + numberPattern + `)(?:\s*/\s*(` + numberPattern + `))?`)
+
+// The answer to a numeric problem, written as a number with an optional tolerance and unit,
+// such as "3", "3.14 +- 0.01", "100+-5%" or "9.8 +- 0.1 m/s^2".
+type NumericAnswer struct {
+	Value     float64
+	Tolerance float64 // How far off an answer may be. A percentage of Value when Relative.
+	Relative  bool
+	Unit      string // Empty for a plain number
+}
+
+// Close enough to count floating point rounding, such as 0.1+0.2, as equal
+const numericEpsilon = 1e-9
+
+func ParseNumericAnswer(s string) (NumericAnswer, error) {
+	value, rest, err := parseLeadingNumber(s)
+	if err != nil {
+		return NumericAnswer{}, err
+	}
+	answer := NumericAnswer{Value: value}
+	if tolerance, ok := strings.CutPrefix(rest, "+-"); ok {
+		answer.Tolerance, rest, err = parseLeadingNumber(tolerance)
+		if err != nil {
+			return NumericAnswer{}, fmt.Errorf("Invalid tolerance: %v", err)
+		}
+		if answer.Tolerance < 0 {
+			return NumericAnswer{}, errors.New("Tolerance cannot be negative")
+		}
+		if unit, ok := strings.CutPrefix(rest, "%"); ok {
+			answer.Relative = true
+			rest = strings.TrimSpace(unit)
+		}
+	}
+	answer.Unit = normalizeUnit(rest)
+	return answer, nil
+}
+
+// Parses a submitted number, with or without the answer's unit
+func ParseNumber(s string) (float64, string, error) {
+	value, rest, err := parseLeadingNumber(s)
+	if err != nil {
+		return 0, "", err
+	}
+	return value, normalizeUnit(rest), nil
+}
+
+// Reports whether a submitted number is within tolerance of the answer.
+// A submission may leave out the unit, but a different unit is wrong.
+func (n NumericAnswer) Matches(submission string) bool {
+	value, unit, err := ParseNumber(submission)
+	if err != nil {
+		return false
+	}
+	if unit != "" && unit != n.Unit {
+		return false
+	}
+	tolerance := n.Tolerance
+	if n.Relative {
+		tolerance = math.Abs(n.Value) * n.Tolerance / 100
+	}
+	return math.Abs(value-n.Value) <= tolerance+numericEpsilon*max(1, math.Abs(n.Value))
+}
+
+// Parses the number at the start of s, returning what follows it with surrounding space trimmed
+func parseLeadingNumber(s string) (float64, string, error) {
+	s = strings.TrimSpace(s)
+	match := leadingNumber.FindStringSubmatchIndex(s)
+	if match == nil {
+		return 0, "", fmt.Errorf("Not a number: %q", s)
+	}
+	value, err := strconv.ParseFloat(s[match[2]:match[3]], 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("Not a number: %q", s)
+	}
+	if match[4] >= 0 {
+		denominator, err := strconv.ParseFloat(s[match[4]:match[5]], 64)
+		if err != nil || denominator == 0 {
+			return 0, "", fmt.Errorf("Invalid fraction: %q", s[:match[1]])
+		}
+		value /= denominator
+	}
+	return value, strings.TrimSpace(s[match[1]:]), nil
+}
+
+// Units are compared ignoring case and spacing, so "M/S" matches "m / s"
+func normalizeUnit(unit string) string {
+	return strings.ToLower(strings.Join(strings.Fields(unit), ""))
+}
@@ -0,0 +1,68 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+)
+
+func TestParseNumericAnswer(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    models.NumericAnswer
+		isValid bool
+	}{
+		{"Integer", "3", models.NumericAnswer{Value: 3}, true},
+		{"Scientific", "-1.5e2", models.NumericAnswer{Value: -150}, true},
+		{"Fraction", "1 / 4", models.NumericAnswer{Value: 0.25}, true},
+		{"Absolute tolerance", "3.14 +- 0.01", models.NumericAnswer{Value: 3.14, Tolerance: 0.01}, true},
+		{"Relative tolerance", "100+-5%", models.NumericAnswer{Value: 100, Tolerance: 5, Relative: true}, true},
+		{"Unit", "9.8+-0.1 M / S", models.NumericAnswer{Value: 9.8, Tolerance: 0.1, Unit: "m/s"}, true},
+		{"Not a number", "abc", models.NumericAnswer{}, false},
+		{"Divide by zero", "1/0", models.NumericAnswer{}, false},
+		{"Missing tolerance", "3+-", models.NumericAnswer{}, false},
+		{"Negative tolerance", "3+--1", models.NumericAnswer{}, false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.ParseNumericAnswer(tt.input)
+			if tt.isValid {
+				testutils.AssertNoError(t, err)
+				testutils.AssertEqual(t, got, tt.want)
+			} else {
+				testutils.AssertHasError(t, err)
+			}
+		})
+	}
+}
+
+func TestNumericAnswerMatches(t *testing.T) {
+	cases := []struct {
+		name       string
+		answer     string
+		submission string
+		want       bool
+	}{
+		{"Exact", "3", "3", true},
+		{"Equivalent forms", "0.5", "1/2", true},
+		{"Float rounding", "0.3", "0.30000000000000004", true},
+		{"Within absolute tolerance", "3.14+-0.01", "3.149", true},
+		{"Outside absolute tolerance", "3.14+-0.01", "3.16", false},
+		{"Within relative tolerance", "200+-5%", "190", true},
+		{"Outside relative tolerance", "200+-5%", "189", false},
+		{"Same unit", "5 cm", "5cm", true},
+		{"Unit left out", "5 cm", "5", true},
+		{"Different unit", "5 cm", "5 m", false},
+		{"Unit on a plain number", "5", "5 apples", false},
+		{"Not a number", "5", "five", false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			answer, err := models.ParseNumericAnswer(tt.answer)
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, answer.Matches(tt.submission), tt.want)
+		})
+	}
+}
@@ -15,8 +15,13 @@ const MaxNumChoices = 4
 type ProblemType string
 
 const (
-	ProblemTypeText   ProblemType = "text"
-	ProblemTypeChoice ProblemType = "choice"
+	ProblemTypeText      ProblemType = "text"
+	ProblemTypeChoice    ProblemType = "choice"
+	ProblemTypeNumeric   ProblemType = "numeric"   // Graded as a number, see NumericAnswer
+	ProblemTypeMulti     ProblemType = "multi"     // Several choices are right. The answer is a JSON array of them.
+	ProblemTypeTrueFalse ProblemType = "truefalse" // Choices are implied, see TrueFalseChoices
+	ProblemTypeOrdering  ProblemType = "ordering"  // The choices are put in sequence. The answer is a JSON array of them in order.
+	ProblemTypeMatching  ProblemType = "matching"  // Each choice is paired with a match. The answer is a JSON object, see ParseMatching.
 )
 
 func (pt ProblemType) String() string {
@@ -25,7 +30,8 @@ func (pt ProblemType) String() string {
 
 func (pt ProblemType) IsValid() bool {
 	switch pt {
-	case ProblemTypeText, ProblemTypeChoice:
+	case ProblemTypeText, ProblemTypeChoice, ProblemTypeNumeric, ProblemTypeMulti, ProblemTypeTrueFalse,
+		ProblemTypeOrdering, ProblemTypeMatching:
 		return true
 	}
 	return false
@@ -46,59 +52,197 @@ func ValidateChoices(problemType ProblemType, choices []string, answer string) e
 
 	switch problemType {
 	case ProblemTypeChoice:
-		if len(choices) < 2 || len(choices) > MaxNumChoices {
-			return fmt.Errorf("Choice type must have at least 2 choices and at most %d choices", MaxNumChoices)
+		if err := validateChoiceList(problemType, choices); err != nil {
+			return err
 		}
-		choiceFound := false
-		seen := make(map[string]struct{}, len(choices))
-		for _, c := range choices {
-			if c == "" {
-				return errors.New("Choice cannot be empty string")
-			}
-			if _, exists := seen[strings.ToLower(c)]; exists {
-				return fmt.Errorf("Duplicate choice found")
+		if !slices.ContainsFunc(choices, func(c string) bool { return strings.EqualFold(c, answer) }) {
+			return fmt.Errorf("Answer must be one of the choices")
+		}
+	case ProblemTypeMulti:
+		if err := validateChoiceList(problemType, choices); err != nil {
+			return err
+		}
+		selection, err := ParseSelection(answer)
+		if err != nil {
+			return err
+		}
+		if len(selection) == 0 {
+			return errors.New("Multi problems must have at least 1 correct choice")
+		}
+		seen := make(map[string]struct{}, len(selection))
+		for _, s := range selection {
+			if !slices.ContainsFunc(choices, func(c string) bool { return strings.EqualFold(c, s) }) {
+				return fmt.Errorf("Answer %q must be one of the choices", s)
 			}
-			seen[strings.ToLower(c)] = struct{}{}
-			if strings.EqualFold(c, answer) {
-				choiceFound = true
+			if _, exists := seen[strings.ToLower(s)]; exists {
+				return fmt.Errorf("Duplicate answer found")
 			}
-		}
-		if !choiceFound {
-			return fmt.Errorf("Answer must be one of the choices")
+			seen[strings.ToLower(s)] = struct{}{}
 		}
 	case ProblemTypeText:
 		if len(choices) != 0 {
 			return fmt.Errorf("Text problems cannot have choices")
 		}
+	case ProblemTypeNumeric:
+		if len(choices) != 0 {
+			return fmt.Errorf("Numeric problems cannot have choices")
+		}
+		if _, err := ParseNumericAnswer(answer); err != nil {
+			return fmt.Errorf("Numeric answer is invalid. %v", err)
+		}
+	case ProblemTypeOrdering:
+		if err := validateChoiceList(problemType, choices); err != nil {
+			return err
+		}
+		order, err := ParseSelection(answer)
+		if err != nil {
+			return err
+		}
+		if len(order) != len(choices) {
+			return fmt.Errorf("Ordering answer must list each of the %d choices once", len(choices))
+		}
+		for _, o := range order {
+			if !slices.ContainsFunc(choices, func(c string) bool { return strings.EqualFold(c, o) }) {
+				return fmt.Errorf("Answer %q must be one of the choices", o)
+			}
+		}
+		// Choices are distinct, so each is listed once if none is missing
+		for _, c := range choices {
+			if !slices.ContainsFunc(order, func(o string) bool { return strings.EqualFold(c, o) }) {
+				return fmt.Errorf("Ordering answer is missing %q", c)
+			}
+		}
+	case ProblemTypeMatching:
+		if err := validateChoiceList(problemType, choices); err != nil {
+			return err
+		}
+		if err := validateMatching(choices, answer); err != nil {
+			return err
+		}
+	case ProblemTypeTrueFalse:
+		// The choices may be left out, or written out as they are implied
+		if len(choices) != 0 && !slices.EqualFunc(choices, TrueFalseChoices, strings.EqualFold) {
+			return fmt.Errorf("Truefalse problems cannot have choices other than %v", TrueFalseChoices)
+		}
+		if _, err := ParseTrueFalse(answer); err != nil {
+			return fmt.Errorf("Truefalse answer is invalid. %v", err)
+		}
 	default:
 		return fmt.Errorf("Invalid problem type; %v", problemType)
 	}
 	return nil
 }
 
+// Checks only text problems have aliases, and that each is a distinct answer of its own
+func ValidateAliases(problemType ProblemType, answer string, aliases []string) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+	if problemType != ProblemTypeText {
+		return fmt.Errorf("%v problems cannot have aliases", problemType)
+	}
+	seen := map[string]struct{}{strings.ToLower(strings.TrimSpace(answer)): {}}
+	for _, a := range aliases {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" {
+			return errors.New("Alias cannot be empty string")
+		}
+		if _, exists := seen[a]; exists {
+			return fmt.Errorf("Duplicate alias found")
+		}
+		seen[a] = struct{}{}
+	}
+	return nil
+}
+
+// Checks a player's answer has the shape its problem needs, so it can be sent again rather than graded as wrong.
+// Ordering answers must be a JSON array and matching answers a JSON object. Other answers may be any string.
+func ValidateAnswerShape(problemType ProblemType, answer string) error {
+	switch problemType {
+	case ProblemTypeOrdering:
+		_, err := ParseSelection(answer)
+		return err
+	case ProblemTypeMatching:
+		_, err := ParseMatching(answer)
+		return err
+	}
+	return nil
+}
+
+// Writes a valid answer the same way whatever form it was given in,
+// so JSON answers share spacing and true/false answers are true or false
+func CanonicalAnswer(problemType ProblemType, answer string) string {
+	switch problemType {
+	case ProblemTypeMulti, ProblemTypeOrdering:
+		if selection, err := ParseSelection(answer); err == nil {
+			return FormatSelection(selection)
+		}
+	case ProblemTypeMatching:
+		if pairs, err := ParseMatching(answer); err == nil {
+			return FormatMatching(pairs)
+		}
+	case ProblemTypeTrueFalse:
+		if value, err := ParseTrueFalse(answer); err == nil {
+			return FormatTrueFalse(value)
+		}
+	}
+	return answer
+}
+
+// Checks the choices of a problem that has them are present, not too many, and distinct
+func validateChoiceList(problemType ProblemType, choices []string) error {
+	if len(choices) < 2 || len(choices) > MaxNumChoices {
+		return fmt.Errorf("%v problems must have at least 2 choices and at most %d choices", problemType, MaxNumChoices)
+	}
+	seen := make(map[string]struct{}, len(choices))
+	for _, c := range choices {
+		if c == "" {
+			return errors.New("Choice cannot be empty string")
+		}
+		if _, exists := seen[strings.ToLower(c)]; exists {
+			return fmt.Errorf("Duplicate choice found")
+		}
+		seen[strings.ToLower(c)] = struct{}{}
+	}
+	return nil
+}
+
 type Problem struct {
 	Id       uuid.UUID
 	Type     ProblemType
 	Question string
 	Choices  []string
 	Answer   string
+	Aliases  []string // Other accepted answers to a text problem
 }
 
 func (p Problem) String() string {
-	return fmt.Sprintf("id: %v, type: %v, question: %v, choices: %v, answer: %v", p.Id, p.Type.String(), p.Question, p.Choices, p.Answer)
+	return fmt.Sprintf("id: %v, type: %v, question: %v, choices: %v, answer: %v, aliases: %v", p.Id, p.Type.String(), p.Question, p.Choices, p.Answer, p.Aliases)
 }
 
 func (p Problem) ToStringSlice() []string {
-	return []string{p.Id.String(), p.Type.String(), p.Question, serializeArray(p.Choices), p.Answer}
+	aliases := p.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return []string{p.Id.String(), p.Type.String(), p.Question, serializeArray(p.Choices), p.Answer, serializeArray(aliases)}
 }
 
 func (p Problem) Equal(b Problem) bool {
-	if p.Id != b.Id || p.Type != b.Type || p.Question != b.Question || !slices.Equal(p.Choices, b.Choices) || p.Answer != b.Answer {
+	if p.Id != b.Id || p.Type != b.Type || p.Question != b.Question || !slices.Equal(p.Choices, b.Choices) || p.Answer != b.Answer ||
+		!slices.Equal(p.Aliases, b.Aliases) {
 		return false
 	}
 	return true
 }
 
+// Reports whether answer matches the problem's answer or one of its aliases, ignoring case and surrounding whitespace.
+// Numeric answers are compared as numbers, a multi-select answer must pick exactly the right choices,
+// and ordering and matching answers must put every choice in its place.
+func (p Problem) IsCorrect(answer string) bool {
+	return p.Grade(answer, CreditModeAllOrNothing) == 1
+}
+
 func serializeArray(arr []string) string {
 	bytes, err := json.Marshal(arr)
 	if err != nil {
@@ -112,6 +256,7 @@ type Question struct {
 	Type     ProblemType
 	Question string
 	Choices  []string
+	Targets  []string `json:",omitempty"` // Matching problems only: what the choices are paired with
 }
 
 func (q Question) String() string {
@@ -27,6 +27,18 @@ func TestParseProblemType(t *testing.T) {
 			models.ProblemTypeChoice,
 			true,
 		},
+		{
+			"Numeric type",
+			"numeric",
+			models.ProblemTypeNumeric,
+			true,
+		},
+		{
+			"True/false type",
+			"TrueFalse",
+			models.ProblemTypeTrueFalse,
+			true,
+		},
 		{
 			"Invalid type",
 			"invalid",
@@ -125,6 +137,167 @@ func TestValidateChoices(t *testing.T) {
 			"Frankie",
 			false,
 		},
+		{
+			"numeric type",
+			models.ProblemTypeNumeric,
+			[]string{},
+			"3.14+-0.01",
+			true,
+		},
+		{
+			"Numeric answer not a number",
+			models.ProblemTypeNumeric,
+			[]string{},
+			"three",
+			false,
+		},
+		{
+			"multi type",
+			models.ProblemTypeMulti,
+			[]string{"Red", "Green", "Blue"},
+			`["red","Blue"]`,
+			true,
+		},
+		{
+			"Multi answer not an array",
+			models.ProblemTypeMulti,
+			[]string{"Red", "Green", "Blue"},
+			"red",
+			false,
+		},
+		{
+			"Multi with no correct choices",
+			models.ProblemTypeMulti,
+			[]string{"Red", "Green", "Blue"},
+			"[]",
+			false,
+		},
+		{
+			"Multi answer not one of choices",
+			models.ProblemTypeMulti,
+			[]string{"Red", "Green", "Blue"},
+			`["red","pink"]`,
+			false,
+		},
+		{
+			"Multi duplicate answer",
+			models.ProblemTypeMulti,
+			[]string{"Red", "Green", "Blue"},
+			`["red","RED"]`,
+			false,
+		},
+		{
+			"Multi with one choice",
+			models.ProblemTypeMulti,
+			[]string{"Red"},
+			`["red"]`,
+			false,
+		},
+		{
+			"Numeric with choices",
+			models.ProblemTypeNumeric,
+			[]string{"3", "4"},
+			"3",
+			false,
+		},
+		{
+			"Ordering",
+			models.ProblemTypeOrdering,
+			[]string{"Bronze", "Iron", "Stone"},
+			`["stone","bronze","iron"]`,
+			true,
+		},
+		{
+			"Ordering missing a choice",
+			models.ProblemTypeOrdering,
+			[]string{"Bronze", "Iron", "Stone"},
+			`["stone","bronze"]`,
+			false,
+		},
+		{
+			"Ordering repeats a choice",
+			models.ProblemTypeOrdering,
+			[]string{"Bronze", "Iron", "Stone"},
+			`["stone","bronze","Stone"]`,
+			false,
+		},
+		{
+			"Ordering answer not one of choices",
+			models.ProblemTypeOrdering,
+			[]string{"Bronze", "Iron", "Stone"},
+			`["stone","bronze","copper"]`,
+			false,
+		},
+		{
+			"Matching",
+			models.ProblemTypeMatching,
+			[]string{"Na", "K"},
+			`{"na":"Sodium","K":"Potassium"}`,
+			true,
+		},
+		{
+			"Matching misses a choice",
+			models.ProblemTypeMatching,
+			[]string{"Na", "K", "Fe"},
+			`{"Na":"Sodium","K":"Potassium"}`,
+			false,
+		},
+		{
+			"Matching pairs an unknown choice",
+			models.ProblemTypeMatching,
+			[]string{"Na", "K"},
+			`{"Na":"Sodium","Fe":"Iron"}`,
+			false,
+		},
+		{
+			"Matching pairs a choice twice in different cases",
+			models.ProblemTypeMatching,
+			[]string{"Na", "K"},
+			`{"Na":"Sodium","na":"Salt"}`,
+			false,
+		},
+		{
+			"Matching uses a match twice",
+			models.ProblemTypeMatching,
+			[]string{"Na", "K"},
+			`{"Na":"Sodium","K":"sodium"}`,
+			false,
+		},
+		{
+			"Matching answer not an object",
+			models.ProblemTypeMatching,
+			[]string{"Na", "K"},
+			`["Sodium","Potassium"]`,
+			false,
+		},
+		{
+			"True/false without choices",
+			models.ProblemTypeTrueFalse,
+			nil,
+			"Yes",
+			true,
+		},
+		{
+			"True/false with its implied choices",
+			models.ProblemTypeTrueFalse,
+			[]string{"true", "false"},
+			"false",
+			true,
+		},
+		{
+			"True/false with other choices",
+			models.ProblemTypeTrueFalse,
+			[]string{"Yes", "No"},
+			"yes",
+			false,
+		},
+		{
+			"True/false answer not true or false",
+			models.ProblemTypeTrueFalse,
+			[]string{},
+			"maybe",
+			false,
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
@@ -138,3 +311,45 @@ func TestValidateChoices(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAliases(t *testing.T) {
+	testutils.AssertNoError(t, models.ValidateAliases(models.ProblemTypeText, "usa", []string{"United States", "US"}))
+	testutils.AssertNoError(t, models.ValidateAliases(models.ProblemTypeChoice, "a", nil))
+	testutils.AssertHasError(t, models.ValidateAliases(models.ProblemTypeChoice, "a", []string{"b"}))
+	testutils.AssertHasError(t, models.ValidateAliases(models.ProblemTypeText, "usa", []string{" "}))
+	testutils.AssertHasError(t, models.ValidateAliases(models.ProblemTypeText, "usa", []string{"US", "us"}))
+	testutils.AssertHasError(t, models.ValidateAliases(models.ProblemTypeText, "usa", []string{"USA"}))
+}
+
+func TestIsCorrectAlias(t *testing.T) {
+	problem := models.Problem{Type: models.ProblemTypeText, Question: "Largest economy", Answer: "usa", Aliases: []string{"United States", "us"}}
+
+	testutils.AssertTrue(t, problem.IsCorrect("USA"))
+	testutils.AssertTrue(t, problem.IsCorrect("united states "))
+	testutils.AssertTrue(t, problem.IsCorrect("US"))
+	testutils.AssertFalse(t, problem.IsCorrect("america"))
+}
+
+func TestIsCorrect(t *testing.T) {
+	problem := models.Problem{Type: models.ProblemTypeText, Question: "Capital of France", Answer: "paris"}
+
+	testutils.AssertTrue(t, problem.IsCorrect("paris"))
+	testutils.AssertTrue(t, problem.IsCorrect(" Paris "))
+	testutils.AssertFalse(t, problem.IsCorrect("london"))
+	testutils.AssertFalse(t, problem.IsCorrect(""))
+}
+
+func TestIsCorrectNumeric(t *testing.T) {
+	problem := models.Problem{Type: models.ProblemTypeNumeric, Question: "1+2", Answer: "3"}
+
+	for _, answer := range []string{"3", "3.0", "3e0", " 3 ", "6/2", "+3"} {
+		testutils.AssertTrue(t, problem.IsCorrect(answer))
+	}
+	testutils.AssertFalse(t, problem.IsCorrect("3.1"))
+	testutils.AssertFalse(t, problem.IsCorrect("three"))
+	testutils.AssertFalse(t, problem.IsCorrect(""))
+
+	half := models.Problem{Type: models.ProblemTypeNumeric, Question: "1/2", Answer: "1/2"}
+	testutils.AssertTrue(t, half.IsCorrect("0.5"))
+	testutils.AssertTrue(t, half.IsCorrect(".5"))
+}
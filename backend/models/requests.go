@@ -11,6 +11,7 @@ type CreateProblemRequest struct {
 	Question string
 	Choices  []string
 	Answer   string
+	Aliases  []string // Other accepted answers. Text problems only.
 }
 
 type EditProblemRequest struct {
@@ -19,6 +20,7 @@ type EditProblemRequest struct {
 	Question string
 	Choices  []string
 	Answer   string
+	Aliases  []string // Other accepted answers. Text problems only.
 }
 
 type StartQuizResponse struct {
@@ -29,22 +31,26 @@ type StartQuizResponse struct {
 
 type QuestionSubmission struct {
 	QuestionId uuid.UUID
-	Answer     string
+	// A JSON array of the picked choices for a multi problem, or of every choice in order for an ordering problem.
+	// A JSON object from each choice to its match for a matching problem.
+	Answer string
 }
 
 // TODO: Change request object
 type EvaluateQuizRequest struct {
 	SessionID           uuid.UUID
 	QuestionSubmissions []QuestionSubmission
+	CreditMode          string // How partly right answers are graded. Defaults to all or nothing.
 }
 
 type EvaluateQuizResponse struct {
-	Score   int
+	Score   float64 // Sum of the credit earned for each question
 	Answers []QuestionResponse
 }
 
 type QuestionResponse struct {
 	Id      uuid.UUID
-	Answer  string
+	Answer  string // The problem's own answer, never one of its aliases
 	Correct bool
+	Score   float64 // Fraction of full credit earned, from 0 to 1
 }
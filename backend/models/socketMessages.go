@@ -1,7 +1,10 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type MessageType string
@@ -16,11 +19,224 @@ const (
 	MessageTypePlayerList   MessageType = "player_list"
 	MessageTypeStartGame    MessageType = "start"
 	MessageTypeNextQuestion MessageType = "question"
+
+	MessageTypeSubmitAnswer    MessageType = "submit_answer"
+	MessageTypeAnswerReceived  MessageType = "answer_received"
+	MessageTypeQuestionClosed  MessageType = "question_closed"
+	MessageTypeGameStatus      MessageType = "game_status"
+	MessageTypeLeaderboard     MessageType = "leaderboard"
+	MessageTypePodium          MessageType = "podium"
+	MessageTypeRoomCreated     MessageType = "room_created"
+	MessageTypeSession         MessageType = "session"
+	MessageTypePlayerState     MessageType = "player_state"
+	MessageTypeHostStatus      MessageType = "host_status"
+	MessageTypeModeration      MessageType = "moderation"
+	MessageTypeTeamList        MessageType = "team_list"
+	MessageTypeElimination     MessageType = "elimination"
+	MessageTypeProgress        MessageType = "progress"
+	MessageTypeQuestionResults MessageType = "question_results"
+	MessageTypeLobbyStatus     MessageType = "lobby_status"
+	MessageTypeTimer           MessageType = "timer"
+	MessageTypeTeamChat        MessageType = "team_chat" // Chat seen only by the sender's team and the host
+
+	// Host only
+	MessageTypeCloseQuestion   MessageType = "close_question"
+	MessageTypeAdvanceQuestion MessageType = "advance_question"
+	MessageTypeKickPlayer      MessageType = "kick_player"
+	MessageTypeBanPlayer       MessageType = "ban_player"
+	MessageTypeMutePlayer      MessageType = "mute_player"
+	MessageTypeRenamePlayer    MessageType = "rename_player"
+	MessageTypePauseGame       MessageType = "pause_game"
+	MessageTypeResumeGame      MessageType = "resume_game"
+	MessageTypeSkipQuestion    MessageType = "skip_question"
+	MessageTypeEndGame         MessageType = "end_game"
+	MessageTypeLockLobby       MessageType = "lock_lobby"
+	MessageTypeUnlockLobby     MessageType = "unlock_lobby"
+)
+
+// Actions reported in a moderation message
+const (
+	ModerationActionKick   = "kick"
+	ModerationActionBan    = "ban"
+	ModerationActionMute   = "mute"
+	ModerationActionUnmute = "unmute"
+	ModerationActionRename = "rename"
 )
 
 type MessageTypeQuestionContent struct {
-	QuestionNumber int    `json:"questionNumber"` //!!! What json type to use here
-	Question       string `json:"question"`
+	QuestionNumber int        `json:"questionNumber"` //!!! What json type to use here
+	QuestionCount  int        `json:"questionCount"`
+	Type           string     `json:"type"`
+	Question       string     `json:"question"`
+	Choices        []string   `json:"choices,omitempty"`     // In the order this recipient sees them
+	Targets        []string   `json:"targets,omitempty"`     // Matching questions only: what the choices are paired with
+	Deadline       *time.Time `json:"deadline,omitempty"`    // When the server stops taking answers. Unset while paused.
+	SuddenDeath    bool       `json:"suddenDeath,omitempty"` // A tie-breaker in an elimination game
+}
+
+type SubmitAnswerContent struct {
+	QuestionNumber int    `json:"questionNumber"`
+	Answer         string `json:"answer"`
+}
+
+type AnswerReceivedContent struct {
+	QuestionNumber int `json:"questionNumber"`
+}
+
+type QuestionClosedContent struct {
+	QuestionNumber int    `json:"questionNumber"`
+	Answer         string `json:"answer"`
+	Skipped        bool   `json:"skipped,omitempty"` // The host skipped the question, so it was not scored
+}
+
+type GameStatusContent struct {
+	Status string `json:"status"`
+	GameId string `json:"gameId,omitempty"` // Sent to the host once the game is done, to fetch its results
+}
+
+type LeaderboardEntry struct {
+	Name         string `json:"name"`
+	Score        int    `json:"score"`
+	CorrectCount int    `json:"correctCount"`
+	Rank         int    `json:"rank"`
+	Eliminated   bool   `json:"eliminated,omitempty"`
+}
+
+type LeaderboardContent struct {
+	QuestionNumber int                `json:"questionNumber"`
+	PlayerCount    int                `json:"playerCount"`
+	Top            []LeaderboardEntry `json:"top"`
+	Position       *LeaderboardEntry  `json:"position,omitempty"` // Only set on the copy sent to each player
+}
+
+// How many players gave one answer to a question
+type AnswerCount struct {
+	Answer  string  `json:"answer"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"` // Of the players who answered
+	Correct bool    `json:"correct"`
+}
+
+// Sent to the host when a question closes
+type QuestionResultsContent struct {
+	QuestionNumber        int           `json:"questionNumber"`
+	Answer                string        `json:"answer"`
+	PlayerCount           int           `json:"playerCount"`
+	AnswerCount           int           `json:"answerCount"`  // Players who answered
+	Distribution          []AnswerCount `json:"distribution"` // Every choice, or each distinct answer. For multi-select, how often each choice was picked.
+	AverageResponseTimeMs int64         `json:"averageResponseTimeMs"`
+	CorrectPlayers        []string      `json:"correctPlayers"` // Fastest first
+}
+
+// Sent to each player when a question closes, in place of the host's results
+type PlayerQuestionResultContent struct {
+	QuestionNumber int     `json:"questionNumber"`
+	Answered       bool    `json:"answered"`
+	Correct        bool    `json:"correct"`
+	Score          float64 `json:"score"` // Fraction of full credit earned
+}
+
+// Sent after each question of an elimination game
+type EliminationContent struct {
+	QuestionNumber int      `json:"questionNumber"`
+	Eliminated     []string `json:"eliminated"`
+	Remaining      []string `json:"remaining"`
+	Reprieved      bool     `json:"reprieved,omitempty"` // Everyone still in missed, so nobody was eliminated
+}
+
+// One row of the progress grid the host sees during a self-paced game
+type PlayerProgress struct {
+	Name           string `json:"name"`
+	QuestionNumber int    `json:"questionNumber"` // The question the player is on
+	Results        []bool `json:"results"`        // Whether each question answered so far was correct
+	Score          int    `json:"score"`
+	Finished       bool   `json:"finished"`
+}
+
+// Sent to the host and spectators whenever a player answers in a self-paced game
+type ProgressContent struct {
+	QuestionCount int              `json:"questionCount"`
+	Deadline      time.Time        `json:"deadline"`
+	Players       []PlayerProgress `json:"players"`
+}
+
+// Sent when a question opens, every tick while it is open, and on pause and resume.
+// In a self-paced game the times are for the whole game.
+type TimerContent struct {
+	QuestionNumber   int        `json:"questionNumber"`
+	StartedAt        time.Time  `json:"startedAt"`
+	Deadline         *time.Time `json:"deadline,omitempty"` // Unset while paused
+	RemainingSeconds int        `json:"remainingSeconds"`
+	Paused           bool       `json:"paused,omitempty"`
+	WholeGame        bool       `json:"wholeGame,omitempty"`
+}
+
+type TeamEntry struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+	Score   int      `json:"score"`
+	Rank    int      `json:"rank"`
+}
+
+type TeamListContent struct {
+	Scoring string      `json:"scoring"` // How member scores are combined: sum or average
+	Teams   []TeamEntry `json:"teams"`
+}
+
+// Tells everyone whether new players can join
+type LobbyStatusContent struct {
+	Locked      bool   `json:"locked"`
+	PlayerCount int    `json:"playerCount"`
+	MaxPlayers  int    `json:"maxPlayers,omitempty"` // Unset when there is no limit
+	LateJoin    string `json:"lateJoin"`             // What happens to players who join once the game has started
+}
+
+type PodiumContent struct {
+	Podium []LeaderboardEntry `json:"podium"`
+}
+
+type RoomContent struct {
+	Code      string    `json:"code"`
+	HostToken uuid.UUID `json:"hostToken"` // Lets the host reclaim the room after losing their connection
+}
+
+// Tells players whether the host is connected. While the host is reconnecting the game is paused.
+type HostStatusContent struct {
+	Connected         bool       `json:"connected"`
+	ReconnectDeadline *time.Time `json:"reconnectDeadline,omitempty"`
+}
+
+// Sent to a player when they join so they can reconnect as themselves
+type SessionContent struct {
+	PlayerId       uuid.UUID `json:"playerId"`
+	ReconnectToken uuid.UUID `json:"reconnectToken"`
+	Team           string    `json:"team,omitempty"`
+}
+
+// Sent to a player who reconnects so they can resume the game
+type PlayerStateContent struct {
+	GameStatus     string                      `json:"gameStatus"`
+	QuestionStatus string                      `json:"questionStatus"`
+	QuestionNumber int                         `json:"questionNumber"`
+	Question       *MessageTypeQuestionContent `json:"question,omitempty"`
+	Answered       bool                        `json:"answered"`
+	Paused         bool                        `json:"paused"`
+	Standing       LeaderboardEntry            `json:"standing"`
+}
+
+// Sent by the host to moderate a player
+type ModerationCommandContent struct {
+	PlayerName string `json:"playerName"`
+	NewName    string `json:"newName,omitempty"`   // Rename only
+	ByAddress  bool   `json:"byAddress,omitempty"` // Ban only: also ban the player's IP address
+	Muted      bool   `json:"muted"`               // Mute only: false unmutes the player
+}
+
+// Tells everyone in the room that the host moderated a player
+type ModerationContent struct {
+	Action     string `json:"action"`
+	PlayerName string `json:"playerName"`
+	NewName    string `json:"newName,omitempty"`
 }
 
 type MessageTextContent struct {
@@ -36,6 +252,7 @@ type Message struct {
 	Timestamp time.Time   `json:"timestamp"`
 	// PlayerID  string      `json:"playerId,omitempty"` TODO: PlayerIDs
 	PlayerName string      `json:"playerName,omitempty"`
+	Team       string      `json:"team,omitempty"` // Set on team chat
 	Content    interface{} `json:"content,omitempty"`
 }
 
@@ -51,3 +268,13 @@ func CreateMessage(Type MessageType, PlayerName string, Content interface{}) Mes
 		Content:    Content,
 	}
 }
+
+// Decodes the content of a received message into target.
+// Content arrives from the socket as generic JSON, so it is re-encoded before decoding.
+func DecodeContent(content interface{}, target interface{}) error {
+	bytes, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, target)
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Everything that happened in a finished live game, kept so teachers can grade it afterwards
+type Transcript struct {
+	GameId      uuid.UUID            `json:"gameId"`
+	Mode        string               `json:"mode"`
+	ScoringMode string               `json:"scoringMode"`
+	StartedAt   time.Time            `json:"startedAt"`
+	EndedAt     time.Time            `json:"endedAt"`
+	Players     []TranscriptPlayer   `json:"players"` // Everyone who played, including those who left
+	Questions   []TranscriptQuestion `json:"questions"`
+	Standings   []LeaderboardEntry   `json:"standings"` // Final standings of the players still in the game, best first
+	Teams       []TeamEntry          `json:"teams,omitempty"`
+	Chat        []ChatEntry          `json:"chat"`
+	HostToken   uuid.UUID            `json:"-"` // Needed to fetch the transcript
+}
+
+type TranscriptPlayer struct {
+	Id           uuid.UUID       `json:"id"`
+	Name         string          `json:"name"`
+	Team         string          `json:"team,omitempty"`
+	Score        int             `json:"score"` // Score when the game ended or when they left
+	CorrectCount int             `json:"correctCount"`
+	Departure    PlayerDeparture `json:"departure,omitempty"` // Empty if they were still in the game at the end
+}
+
+// Why a player is no longer in a game
+type PlayerDeparture string
+
+const (
+	PlayerDepartureLeft   PlayerDeparture = "left"   // Did not reconnect in time
+	PlayerDepartureKicked PlayerDeparture = "kicked" // Kicked or banned by the host
+)
+
+type TranscriptQuestion struct {
+	QuestionNumber int                    `json:"questionNumber"`
+	QuestionId     uuid.UUID              `json:"questionId"`
+	Type           string                 `json:"type"`
+	Question       string                 `json:"question"`
+	Choices        []string               `json:"choices,omitempty"`
+	Answers        []TranscriptAnswer     `json:"answers"` // In the order they were submitted
+	Results        QuestionResultsContent `json:"results"`
+}
+
+type TranscriptAnswer struct {
+	PlayerId       uuid.UUID `json:"playerId"`
+	PlayerName     string    `json:"playerName"`
+	Answer         string    `json:"answer"`
+	Correct        bool      `json:"correct"`
+	Score          float64   `json:"score"` // Fraction of full credit earned
+	SubmittedAt    time.Time `json:"submittedAt"`
+	ResponseTimeMs int64     `json:"responseTimeMs"`
+}
+
+type ChatEntry struct {
+	PlayerName string    `json:"playerName"`
+	Text       string    `json:"text"`
+	Team       string    `json:"team,omitempty"` // Set for team chat
+	SentAt     time.Time `json:"sentAt"`
+}
+
+type GameEventKind string
+
+const (
+	GameEventBroadcast   GameEventKind = "broadcast"    // A message sent to everyone in the room
+	GameEventHostCommand GameEventKind = "host_command" // A message the host sent to the server
+	// A message sent to the host and spectators in place of a broadcast, such as the leaderboard
+	GameEventSpectator GameEventKind = "spectator"
+)
+
+// One entry of a game's event log
+type GameEvent struct {
+	At      time.Time     `json:"at"`
+	Kind    GameEventKind `json:"kind"`
+	Message Message       `json:"message"`
+}
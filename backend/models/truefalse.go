@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The choices a true/false problem is shown with. They are implied, so the problem itself has none.
+var TrueFalseChoices = []string{"True", "False"}
+
+// Words accepted as an answer to a true/false problem, in English and a few other languages
+var trueFalseWords = map[string]bool{
+	"true": true, "t": true, "yes": true, "y": true,
+	"false": false, "f": false, "no": false, "n": false,
+	"vrai": true, "oui": true, "faux": false, "non": false, // French
+	"verdadero": true, "sí": true, "si": true, "falso": false, // Spanish, Italian and Portuguese falso
+	"vero": true, "verdadeiro": true, "sim": true, "não": false, "nao": false, // Italian and Portuguese
+	"wahr": true, "ja": true, "falsch": false, "nein": false, // German
+}
+
+// Parses an answer to a true/false problem, such as true, F, yes or oui
+func ParseTrueFalse(s string) (bool, error) {
+	value, ok := trueFalseWords[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return false, fmt.Errorf("%q is not true or false", s)
+	}
+	return value, nil
+}
+
+// Writes a true/false answer the way it is stored
+func FormatTrueFalse(value bool) string {
+	if value {
+		return "true"
+	}
+	return "false"
+}
+
+// Gets the choices players pick from, which true/false problems have without writing them out
+func (p Problem) DisplayChoices() []string {
+	if p.Type == ProblemTypeTrueFalse {
+		return slices.Clone(TrueFalseChoices)
+	}
+	return p.Choices
+}
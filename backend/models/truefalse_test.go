@@ -0,0 +1,69 @@
+package models_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/testutils"
+)
+
+func TestParseTrueFalse(t *testing.T) {
+	cases := []struct {
+		input string
+		want  bool
+	}{
+		{"true", true},
+		{" T ", true},
+		{"Yes", true},
+		{"y", true},
+		{"oui", true},
+		{"Sí", true},
+		{"ja", true},
+		{"FALSE", false},
+		{"f", false},
+		{"no", false},
+		{"faux", false},
+		{"falsch", false},
+		{"não", false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := models.ParseTrueFalse(tt.input)
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, got, tt.want)
+		})
+	}
+
+	for _, input := range []string{"", "maybe", "truee", "1"} {
+		_, err := models.ParseTrueFalse(input)
+		testutils.AssertHasError(t, err)
+	}
+}
+
+func TestGradeTrueFalse(t *testing.T) {
+	problem := models.Problem{Type: models.ProblemTypeTrueFalse, Question: "The sun is a star", Answer: "true"}
+
+	for _, answer := range []string{"True", "t", "yes", "vrai", "wahr"} {
+		testutils.AssertTrue(t, problem.IsCorrect(answer))
+	}
+	testutils.AssertFalse(t, problem.IsCorrect("false"))
+	testutils.AssertFalse(t, problem.IsCorrect("nein"))
+	testutils.AssertFalse(t, problem.IsCorrect("maybe"))
+	testutils.AssertEqual(t, problem.Grade("no", models.CreditModeProportional), 0.0)
+}
+
+func TestDisplayChoices(t *testing.T) {
+	trueFalse := models.Problem{Type: models.ProblemTypeTrueFalse, Question: "The sun is a star", Answer: "true"}
+	testutils.AssertTrue(t, slices.Equal(trueFalse.DisplayChoices(), []string{"True", "False"}))
+
+	choice := models.Problem{Type: models.ProblemTypeChoice, Question: "Largest planet", Choices: []string{"Mars", "Jupiter"}, Answer: "jupiter"}
+	testutils.AssertTrue(t, slices.Equal(choice.DisplayChoices(), choice.Choices))
+}
+
+func TestCanonicalAnswer(t *testing.T) {
+	testutils.AssertEqual(t, models.CanonicalAnswer(models.ProblemTypeTrueFalse, "Oui"), "true")
+	testutils.AssertEqual(t, models.CanonicalAnswer(models.ProblemTypeTrueFalse, "N"), "false")
+	testutils.AssertEqual(t, models.CanonicalAnswer(models.ProblemTypeMulti, `[ "red", "blue" ]`), `["red","blue"]`)
+	testutils.AssertEqual(t, models.CanonicalAnswer(models.ProblemTypeText, "Paris"), "Paris")
+}
@@ -51,9 +51,13 @@ func setupGame(fileName string, random bool) quizgame {
 func (qg *quizgame) startGame(in io.Reader, done chan<- bool) {
 	reader := bufio.NewScanner(in)
 	for _, problem := range qg.problems {
-		fmt.Println(problem.Question)
+		if problem.Type == models.ProblemTypeTrueFalse {
+			fmt.Printf("%v (true/false)\n", problem.Question)
+		} else {
+			fmt.Println(problem.Question)
+		}
 		answer := utils.CleanInput(readLine(reader))
-		if answer == problem.Answer {
+		if problem.IsCorrect(answer) {
 			fmt.Println("Correct!")
 			qg.score++
 		} else {
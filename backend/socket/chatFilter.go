@@ -0,0 +1,239 @@
+package socket
+
+import (
+	"bufio"
+	"cmp"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+)
+
+// One step of the chat filter chain. Senders are identified by player id, so limits survive reconnects.
+// Returns the text to pass on, which may be rewritten, or an error telling the sender why the message was dropped.
+type ChatFilter interface {
+	Filter(senderId uuid.UUID, text string) (string, error)
+}
+
+// Runs each filter in turn, stopping at the first that drops the message
+type ChatFilterChain []ChatFilter
+
+func (chain ChatFilterChain) Filter(senderId uuid.UUID, text string) (string, error) {
+	for _, filter := range chain {
+		var err error
+		text, err = filter.Filter(senderId, text)
+		if err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+type ChatFilterOptions struct {
+	ProfanityWords  []string      // Masked wherever they appear as whole words
+	MaxLength       int           // Characters per message. 0 for no limit.
+	Burst           int           // Messages a sender can send at once. 0 for no rate limit.
+	RefillInterval  time.Duration // How long it takes a sender to earn back one message
+	DuplicateWindow time.Duration // How long the same message cannot be sent again. 0 to allow repeats.
+}
+
+// Words masked when no word list is configured
+var DefaultProfanityWords = []string{"ass", "asshole", "bastard", "bitch", "crap", "damn", "dick", "fuck", "fucking", "piss", "shit"}
+
+func DefaultChatFilterOptions() ChatFilterOptions {
+	return ChatFilterOptions{
+		ProfanityWords:  DefaultProfanityWords,
+		MaxLength:       200,
+		Burst:           5,
+		RefillInterval:  2 * time.Second,
+		DuplicateWindow: 30 * time.Second,
+	}
+}
+
+// Builds the chain for a room. Length is checked first so an oversized message costs nothing,
+// and profanity is masked last so duplicates are compared as they were typed.
+func NewChatFilterChain(options ChatFilterOptions) ChatFilterChain {
+	chain := ChatFilterChain{}
+	if options.MaxLength > 0 {
+		chain = append(chain, MaxLengthFilter{MaxLength: options.MaxLength})
+	}
+	if options.Burst > 0 {
+		chain = append(chain, NewRateLimitFilter(options.Burst, options.RefillInterval))
+	}
+	if options.DuplicateWindow > 0 {
+		chain = append(chain, NewDuplicateFilter(options.DuplicateWindow))
+	}
+	if len(options.ProfanityWords) > 0 {
+		chain = append(chain, NewProfanityFilter(options.ProfanityWords))
+	}
+	return chain
+}
+
+// Reads a word list with one word per line. Blank lines and lines starting with # are skipped.
+func ReadWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	words := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words, scanner.Err()
+}
+
+// Drops messages longer than MaxLength characters
+type MaxLengthFilter struct {
+	MaxLength int
+}
+
+func (f MaxLengthFilter) Filter(senderId uuid.UUID, text string) (string, error) {
+	if utf8.RuneCountInString(text) > f.MaxLength {
+		return "", &types.ErrChatTooLong{MaxLength: f.MaxLength}
+	}
+	return text, nil
+}
+
+// Replaces each letter of a listed word with *. Words are matched whole and regardless of case.
+// A word ends at anything but a letter or digit in any script, which \b only knows for ASCII.
+type ProfanityFilter struct {
+	pattern *regexp.Regexp // nil when there are no words
+}
+
+func NewProfanityFilter(words []string) *ProfanityFilter {
+	quoted := []string{}
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return &ProfanityFilter{}
+	}
+	// Longest first, so a word is not cut short by a shorter word it starts with
+	slices.SortFunc(quoted, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	return &ProfanityFilter{pattern: regexp.MustCompile(`(?i)(?:` + strings.Join(quoted, "|") + `)`)}
+}
+
+func (f *ProfanityFilter) Filter(senderId uuid.UUID, text string) (string, error) {
+	if f.pattern == nil {
+		return text, nil
+	}
+	var masked strings.Builder
+	copied := 0
+	for start := 0; start < len(text); {
+		loc := f.pattern.FindStringIndex(text[start:])
+		if loc == nil {
+			break
+		}
+		from, to := start+loc[0], start+loc[1]
+		before, _ := utf8.DecodeLastRuneInString(text[:from])
+		after, _ := utf8.DecodeRuneInString(text[to:])
+		if !isWordRune(before) && !isWordRune(after) {
+			masked.WriteString(text[copied:from])
+			masked.WriteString(strings.Repeat("*", utf8.RuneCountInString(text[from:to])))
+			copied, start = to, to
+			continue
+		}
+		// Part of a longer word, but a listed word may still start inside it
+		_, size := utf8.DecodeRuneInString(text[from:])
+		start = from + size
+	}
+	masked.WriteString(text[copied:])
+	return masked.String(), nil
+}
+
+// Whether a rune continues a word. Marks count, as they may be the accent of the letter before.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// Gives each sender a bucket of burst messages that refills by one every refillInterval
+type RateLimitFilter struct {
+	burst          int
+	refillInterval time.Duration
+	buckets        map[uuid.UUID]*tokenBucket
+	mutex          sync.Mutex
+}
+
+type tokenBucket struct {
+	tokens     float64
+	refilledAt time.Time
+}
+
+func NewRateLimitFilter(burst int, refillInterval time.Duration) *RateLimitFilter {
+	return &RateLimitFilter{
+		burst:          burst,
+		refillInterval: refillInterval,
+		buckets:        make(map[uuid.UUID]*tokenBucket),
+	}
+}
+
+func (f *RateLimitFilter) Filter(senderId uuid.UUID, text string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := time.Now()
+	bucket, ok := f.buckets[senderId]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(f.burst), refilledAt: now}
+		f.buckets[senderId] = bucket
+	}
+	if f.refillInterval > 0 {
+		earned := float64(now.Sub(bucket.refilledAt)) / float64(f.refillInterval)
+		bucket.tokens = min(bucket.tokens+earned, float64(f.burst))
+	}
+	bucket.refilledAt = now
+	if bucket.tokens < 1 {
+		retryAfter := time.Duration((1 - bucket.tokens) * float64(f.refillInterval))
+		return "", &types.ErrChatRateLimited{RetryAfter: retryAfter}
+	}
+	bucket.tokens--
+	return text, nil
+}
+
+// Drops a message the sender already sent within the window. Case and spacing are ignored.
+type DuplicateFilter struct {
+	window   time.Duration
+	lastSent map[uuid.UUID]sentMessage
+	mutex    sync.Mutex
+}
+
+type sentMessage struct {
+	text   string
+	sentAt time.Time
+}
+
+func NewDuplicateFilter(window time.Duration) *DuplicateFilter {
+	return &DuplicateFilter{
+		window:   window,
+		lastSent: make(map[uuid.UUID]sentMessage),
+	}
+}
+
+func (f *DuplicateFilter) Filter(senderId uuid.UUID, text string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := time.Now()
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if last, ok := f.lastSent[senderId]; ok && last.text == normalized && now.Sub(last.sentAt) < f.window {
+		return "", &types.ErrChatDuplicate{}
+	}
+	f.lastSent[senderId] = sentMessage{text: normalized, sentAt: now}
+	return text, nil
+}
@@ -0,0 +1,122 @@
+package socket_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adettinger/go-quizgame/socket"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfanityFilter(t *testing.T) {
+	filter := socket.NewProfanityFilter([]string{"darn", "heck", "σκατά", " "})
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"Masks whole words", "oh darn it", "oh **** it"},
+		{"Ignores case", "DARN and Heck", "**** and ****"},
+		{"Leaves longer words alone", "darning", "darning"},
+		{"Punctuation ends a word", "darn!", "****!"},
+		{"Letters outside ASCII continue a word", "darné ädarn", "darné ädarn"},
+		{"Masks words with letters outside ASCII", "ΣΚΑΤΆ!", "*****!"},
+		{"Masks each of several words", "darn darn", "**** ****"},
+		{"Finds a word after a longer one containing it", "undarn darn", "undarn ****"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Filter(uuid.New(), tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("No words", func(t *testing.T) {
+		got, err := socket.NewProfanityFilter(nil).Filter(uuid.New(), "darn")
+		assert.NoError(t, err)
+		assert.Equal(t, "darn", got)
+	})
+}
+
+func TestMaxLengthFilter(t *testing.T) {
+	filter := socket.MaxLengthFilter{MaxLength: 3}
+	_, err := filter.Filter(uuid.New(), "héé")
+	assert.NoError(t, err, "Length is counted in characters, not bytes")
+	_, err = filter.Filter(uuid.New(), "abcd")
+	var tooLongErr *types.ErrChatTooLong
+	assert.True(t, errors.As(err, &tooLongErr))
+}
+
+func TestRateLimitFilter(t *testing.T) {
+	filter := socket.NewRateLimitFilter(2, 100*time.Millisecond)
+	alex, bob := uuid.New(), uuid.New()
+
+	for range 2 {
+		_, err := filter.Filter(alex, "hi")
+		assert.NoError(t, err)
+	}
+	_, err := filter.Filter(alex, "hi")
+	var limitedErr *types.ErrChatRateLimited
+	require.True(t, errors.As(err, &limitedErr))
+	assert.True(t, limitedErr.RetryAfter > 0 && limitedErr.RetryAfter <= 100*time.Millisecond)
+
+	// Each sender has their own bucket
+	_, err = filter.Filter(bob, "hi")
+	assert.NoError(t, err)
+
+	time.Sleep(120 * time.Millisecond)
+	_, err = filter.Filter(alex, "hi")
+	assert.NoError(t, err)
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	filter := socket.NewDuplicateFilter(100 * time.Millisecond)
+	alex := uuid.New()
+
+	_, err := filter.Filter(alex, "Hello  there")
+	assert.NoError(t, err)
+	_, err = filter.Filter(alex, "hello there")
+	var duplicateErr *types.ErrChatDuplicate
+	assert.True(t, errors.As(err, &duplicateErr))
+	_, err = filter.Filter(uuid.New(), "hello there")
+	assert.NoError(t, err, "Another sender may say the same thing")
+
+	time.Sleep(120 * time.Millisecond)
+	_, err = filter.Filter(alex, "hello there")
+	assert.NoError(t, err)
+}
+
+func TestChatFilterChain(t *testing.T) {
+	chain := socket.NewChatFilterChain(socket.ChatFilterOptions{ProfanityWords: []string{"darn"}, MaxLength: 10, Burst: 1, RefillInterval: time.Hour})
+
+	got, err := chain.Filter(uuid.New(), "darn")
+	assert.NoError(t, err)
+	assert.Equal(t, "****", got)
+
+	// A message dropped early in the chain does not reach later filters
+	sender := uuid.New()
+	_, err = chain.Filter(sender, strings.Repeat("a", 11))
+	assert.Error(t, err)
+	_, err = chain.Filter(sender, "hi")
+	assert.NoError(t, err)
+}
+
+func TestReadWordList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# Words to mask\ndarn\n\n  heck \n"), 0644))
+
+	words, err := socket.ReadWordList(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"darn", "heck"}, words)
+
+	_, err = socket.ReadWordList(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
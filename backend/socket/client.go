@@ -30,12 +30,20 @@ type Client struct {
 	Manager  *Manager
 	Send     chan models.Message
 	UserData UserData
+	// Closed by the manager when it drops the client, such as a player the host kicked.
+	// The writePump then sends what is queued and closes the connection, which ends the readPump.
+	Closing chan struct{}
 }
 
 type UserData struct {
 	IsHost   bool
 	PlayerId uuid.UUID
 	Name     string
+	// Spectators watch the game, such as on a projector, but cannot chat or answer
+	IsSpectator bool
+	// Set when a player rejoins with their reconnect token, so no join is announced
+	Reconnected bool
+	Address     string // IP address the client connected from
 }
 
 func (client Client) Logf(message string, args ...interface{}) {
@@ -77,3 +85,16 @@ func (client *Client) ErrorAndKill(errorMsg string) {
 	close(client.Send)
 	client.Conn.Close()
 }
+
+// Queues an error message for the client without blocking
+func (client *Client) SendError(errorMsg string) {
+	select {
+	case client.Send <- models.CreateMessage(
+		models.MessageTypeError,
+		"System",
+		models.MessageTextContent{Text: errorMsg},
+	):
+	default:
+		client.Logf("Client send channel full, skipping error message")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	livegame "github.com/adettinger/go-quizgame/liveGame"
 	"github.com/adettinger/go-quizgame/models"
@@ -12,8 +13,9 @@ import (
 	"github.com/google/uuid"
 )
 
-// Manager manages WebSocket connections
+// Manager manages the WebSocket connections of one live game room
 type Manager struct {
+	Code          string // Join code of the room
 	PlayerClients map[uuid.UUID]*Client
 	HostClient    *Client
 	Register      chan *Client
@@ -22,11 +24,31 @@ type Manager struct {
 	LiveGameStore *livegame.LiveGameStore
 	QuestionStore *webserver.QuestionStore
 	mutex         sync.RWMutex
+	quit          chan struct{}
+	stopOnce      sync.Once
+	onEmpty       func() // Called once the room has no host and no players
+
+	// Read-only connections that are sent the game's broadcasts
+	SpectatorClients map[uuid.UUID]*Client
+
+	// Connections the server dropped whose readPump has not exited yet.
+	// Their send channel is closed when the readPump unregisters them, so it never sends on a closed channel.
+	closingClients map[uuid.UUID]*Client
+	drop           chan *Client
+
+	// While the host is away the game is paused until they reconnect or the grace period ends
+	hostGracePeriod time.Duration
+	hostGraceTimer  *time.Timer
+
+	chatFilter ChatFilter
 }
 
+// How long a game waits for its host to reconnect before it is ended
+const HostReconnectGracePeriod = 60 * time.Second
+
 // Creates a new WebSocket manager
 func NewManager(qs *webserver.QuestionStore) *Manager {
-	return &Manager{
+	m := &Manager{
 		PlayerClients: make(map[uuid.UUID]*Client),
 		HostClient:    nil,
 		Register:      make(chan *Client),
@@ -34,20 +56,35 @@ func NewManager(qs *webserver.QuestionStore) *Manager {
 		Broadcast:     make(chan models.Message),
 		LiveGameStore: livegame.NewLiveGameStore(qs),
 		QuestionStore: qs,
+		quit:          make(chan struct{}),
+
+		SpectatorClients: make(map[uuid.UUID]*Client),
+		closingClients:   make(map[uuid.UUID]*Client),
+		drop:             make(chan *Client),
+		hostGracePeriod:  HostReconnectGracePeriod,
+		chatFilter:       NewChatFilterChain(DefaultChatFilterOptions()),
 	}
+	m.LiveGameStore.SetNotifier(m)
+	return m
 }
 
-// Begins the WebSocket manager's operations
+// Begins the WebSocket manager's operations. Runs until Stop is called.
 func (m *Manager) Start() {
 	for {
 		select {
+		case <-m.quit:
+			log.Printf("Manager for room %s stopped", m.Code)
+			return
 		case client := <-m.Register:
 			func() {
 				// TODO: Check for error response when adding clients
 				m.AddClient(client)
 				client.Logf("Client added to manager")
 			}()
-			if !client.UserData.IsHost {
+			if client.UserData.IsHost && client.UserData.Reconnected && m.stopHostGrace() {
+				go m.hostReconnected()
+			}
+			if !client.UserData.IsHost && !client.UserData.IsSpectator && !client.UserData.Reconnected {
 				go func() {
 					log.Printf("Broadcasting join message for %s", client.UserData.Name)
 					m.BroadcastMessage(models.CreateMessage(
@@ -58,53 +95,27 @@ func (m *Manager) Start() {
 				}()
 			}
 		case client := <-m.Unregister:
-			if _, ok := m.PlayerClients[client.ID]; ok {
-				leaveMsg := models.CreateMessage(
-					models.MessageTypeLeave,
-					client.UserData.Name,
-					models.MessageTextContent{Text: "has left the game"})
-				func() {
-					m.mutex.Lock()
-					defer m.mutex.Unlock()
-					delete(m.PlayerClients, client.ID)
-					close(client.Send)
-					client.Logf("Client disconnected: %s")
-				}()
-
-				go func() {
-					// TODO: Removing player from game store can be done in controller that writes to unregister
-					m.LiveGameStore.RemovePlayerByName(client.UserData.Name)
-					log.Printf("Broadcasting leave message for %s", client.UserData.Name)
-					m.BroadcastMessage(leaveMsg)
-				}()
+			if !m.finishClosing(client) {
+				m.removeClient(client, true)
 			}
-			if m.HostClient != nil && m.HostClient.ID == client.ID {
-				log.Print("Host is leaving game")
-				leaveMsg := models.CreateMessage(
-					models.MessageTypeLeave,
-					"Host",
-					models.MessageTextContent{Text: "has left the game"})
-				func() {
-					m.mutex.Lock()
-					defer m.mutex.Unlock()
-					m.HostClient = nil
-					close(client.Send)
-					client.Logf("Client disconnected: %s")
-				}()
-
-				go func() {
-					// TODO: Removing player from game store can be done in controller that writes to unregister
-					log.Printf("Broadcasting leave message for %s", client.UserData.Name)
-					m.BroadcastMessage(leaveMsg)
-					for _, client := range m.PlayerClients {
-						m.Unregister <- client
-					}
-					m.LiveGameStore.KillGame()
-				}()
-				log.Print("Killed game")
+		case client := <-m.drop:
+			func() {
+				m.mutex.Lock()
+				defer m.mutex.Unlock()
+				// The connection may have dropped by itself first
+				_, isPlayer := m.PlayerClients[client.ID]
+				_, isSpectator := m.SpectatorClients[client.ID]
+				if isPlayer || isSpectator {
+					m.dropClientLocked(client)
+				}
+			}()
+			if m.isEmpty() && m.onEmpty != nil {
+				log.Printf("Room %s is empty", m.Code)
+				go m.onEmpty()
 			}
 		case message := <-m.Broadcast:
 			log.Printf("Broadcasting message of type %s from %s", message.Type, message.PlayerName)
+			m.LiveGameStore.RecordEvent(models.GameEventBroadcast, message)
 
 			// Efficiency concern: Copying clients to avoid holding lock during sends
 			var clients map[uuid.UUID]*Client
@@ -112,13 +123,16 @@ func (m *Manager) Start() {
 				m.mutex.Lock()
 				defer m.mutex.Unlock()
 
-				clients = make(map[uuid.UUID]*Client, len(m.PlayerClients)+1)
+				clients = make(map[uuid.UUID]*Client, len(m.PlayerClients)+len(m.SpectatorClients)+1)
 				if m.HostClient != nil {
 					clients[m.HostClient.ID] = m.HostClient
 				}
 				for id, client := range m.PlayerClients {
 					clients[id] = client
 				}
+				for id, client := range m.SpectatorClients {
+					clients[id] = client
+				}
 			}()
 
 			log.Printf("Sending to %d clients", len(clients))
@@ -131,10 +145,7 @@ func (m *Manager) Start() {
 					default:
 						client.Logf("Send buffer full, closing")
 
-						// Use a goroutine to avoid deadlock when unregistering
-						go func() {
-							m.Unregister <- client
-						}()
+						m.removeClient(client, false)
 					}
 				}(id, client)
 			}
@@ -142,6 +153,73 @@ func (m *Manager) Start() {
 	}
 }
 
+// Removes a client from the room, announcing the host or a player as gone.
+// readPumpDone is false when the client's readPump may still send on its channel.
+func (m *Manager) removeClient(client *Client, readPumpDone bool) {
+	if _, ok := m.SpectatorClients[client.ID]; ok {
+		func() {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			delete(m.SpectatorClients, client.ID)
+			m.releaseClientLocked(client, readPumpDone)
+			client.Logf("Spectator disconnected")
+		}()
+	}
+	if _, ok := m.PlayerClients[client.ID]; ok {
+		func() {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			delete(m.PlayerClients, client.ID)
+			m.releaseClientLocked(client, readPumpDone)
+			client.Logf("Client disconnected: %s")
+		}()
+
+		go func() {
+			// A newer connection for the same player replaced this one
+			if m.hasPlayerClient(client.UserData.PlayerId) {
+				return
+			}
+			// The game store announces the leave if the player does not reconnect in time
+			if err := m.LiveGameStore.DisconnectPlayer(client.UserData.PlayerId); err != nil {
+				client.Logf("Failed to disconnect player", err)
+			}
+		}()
+	}
+	if m.HostClient != nil && m.HostClient.ID == client.ID {
+		log.Print("Host lost connection")
+		var deadline time.Time
+		func() {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			m.HostClient = nil
+			m.releaseClientLocked(client, readPumpDone)
+			client.Logf("Client disconnected: %s")
+			deadline = time.Now().Add(m.hostGracePeriod)
+			m.hostGraceTimer = time.AfterFunc(m.hostGracePeriod, m.expireHost)
+		}()
+
+		// Pause before handling anything else so a quick reconnect cannot resume first
+		m.LiveGameStore.HostDisconnected()
+		go m.announceHostReconnecting(deadline)
+	}
+	if m.isEmpty() && m.onEmpty != nil {
+		log.Printf("Room %s is empty", m.Code)
+		go m.onEmpty()
+	}
+}
+
+// Closes a removed client's send channel. While its readPump may still send on the channel,
+// tells its writePump to close the connection instead and waits for the readPump to unregister it.
+// Caller must hold the mutex.
+func (m *Manager) releaseClientLocked(client *Client, readPumpDone bool) {
+	if readPumpDone {
+		close(client.Send)
+		return
+	}
+	m.closingClients[client.ID] = client
+	close(client.Closing)
+}
+
 // SendToClient sends a message to a specific client
 func (m *Manager) SendToClient(clientID uuid.UUID, message models.Message) bool {
 	m.mutex.Lock()
@@ -156,10 +234,273 @@ func (m *Manager) SendToClient(clientID uuid.UUID, message models.Message) bool
 	return true
 }
 
+// Sends a message to the client of a player without blocking
+func (m *Manager) SendToPlayer(playerId uuid.UUID, message models.Message) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, client := range m.PlayerClients {
+		if client.UserData.PlayerId == playerId {
+			return trySend(client, message)
+		}
+	}
+	return false
+}
+
+// Sends a message to the host client without blocking
+func (m *Manager) SendToHost(message models.Message) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.HostClient == nil {
+		return false
+	}
+	return trySend(m.HostClient, message)
+}
+
+// Sends a message to every spectator client without blocking
+func (m *Manager) SendToSpectators(message models.Message) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, client := range m.SpectatorClients {
+		trySend(client, message)
+	}
+}
+
 // BroadcastMessage sends a message to all connected clients
 func (m *Manager) BroadcastMessage(message models.Message) {
 	log.Printf("Queueing broadcast message of type %s", message.Type)
-	m.Broadcast <- message
+	select {
+	case m.Broadcast <- message:
+	case <-m.quit:
+		log.Printf("Manager stopped, dropping message of type %s", message.Type)
+	}
+}
+
+// Queues a client to be added to the manager. Returns false if the manager has stopped, as when its room closed.
+func (m *Manager) RegisterClient(client *Client) bool {
+	// Checked first, as the Start loop may still take one last client while stopping
+	select {
+	case <-m.quit:
+		return false
+	default:
+	}
+	select {
+	case m.Register <- client:
+		return true
+	case <-m.quit:
+		return false
+	}
+}
+
+// Queues a client to be removed from the manager
+func (m *Manager) UnregisterClient(client *Client) {
+	select {
+	case m.Unregister <- client:
+	case <-m.quit:
+	}
+}
+
+// Stops the manager's Start loop. Safe to call more than once.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.quit)
+	})
+}
+
+// Stops sending to a client without announcing it as gone, such as a connection replaced by a newer one.
+// Caller must hold the mutex.
+func (m *Manager) dropClientLocked(client *Client) {
+	delete(m.PlayerClients, client.ID)
+	delete(m.SpectatorClients, client.ID)
+	if m.HostClient != nil && m.HostClient.ID == client.ID {
+		m.HostClient = nil
+	}
+	m.releaseClientLocked(client, false)
+}
+
+// Closes the send channel of a dropped client once its readPump has unregistered it.
+// Returns false if the server did not drop the client.
+func (m *Manager) finishClosing(client *Client) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.closingClients[client.ID]; !ok {
+		return false
+	}
+	delete(m.closingClients, client.ID)
+	close(client.Send)
+	client.Logf("Dropped client disconnected")
+	return true
+}
+
+// Drops any connection for the player other than the one to keep.
+// Queued behind earlier broadcasts, so the player still gets them.
+func (m *Manager) DisconnectOtherClients(playerId uuid.UUID, keepClientId uuid.UUID) {
+	for _, client := range m.playerClientList() {
+		if client.UserData.PlayerId == playerId && client.ID != keepClientId {
+			client.Logf("Replaced by a newer connection")
+			m.queueDrop(client)
+		}
+	}
+}
+
+// Drops a player or spectator once the broadcasts queued before it have been sent
+func (m *Manager) queueDrop(client *Client) {
+	select {
+	case m.drop <- client:
+	case <-m.quit:
+	}
+}
+
+// Drops every connection of a player, such as one removed by the host
+func (m *Manager) DisconnectPlayerClients(playerId uuid.UUID) {
+	m.DisconnectOtherClients(playerId, uuid.Nil)
+}
+
+// Gets the address a player connected from
+func (m *Manager) PlayerAddress(playerId uuid.UUID) (string, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for _, client := range m.PlayerClients {
+		if client.UserData.PlayerId == playerId {
+			return client.UserData.Address, true
+		}
+	}
+	return "", false
+}
+
+func (m *Manager) SetHostReconnectGracePeriod(d time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.hostGracePeriod = d
+}
+
+// Replaces the filters chat in this room goes through
+func (m *Manager) SetChatFilter(filter ChatFilter) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.chatFilter = filter
+}
+
+// Runs a player's chat message through the room's filters. The host's chat is not filtered.
+func (m *Manager) FilterChat(client *Client, text string) (string, error) {
+	if client.UserData.IsHost {
+		return text, nil
+	}
+	m.mutex.RLock()
+	filter := m.chatFilter
+	m.mutex.RUnlock()
+	if filter == nil {
+		return text, nil
+	}
+	return filter.Filter(client.UserData.PlayerId, text)
+}
+
+// Tells players the game is paused until the host reconnects
+func (m *Manager) announceHostReconnecting(deadline time.Time) {
+	log.Printf("Waiting for host of room %s to reconnect", m.Code)
+	m.BroadcastMessage(models.CreateMessage(
+		models.MessageTypeHostStatus,
+		"System",
+		models.HostStatusContent{Connected: false, ReconnectDeadline: &deadline},
+	))
+}
+
+// Resumes the game once the host has reclaimed it
+func (m *Manager) hostReconnected() {
+	m.LiveGameStore.HostReconnected()
+	log.Printf("Host of room %s reconnected", m.Code)
+	m.BroadcastMessage(models.CreateMessage(
+		models.MessageTypeHostStatus,
+		"System",
+		models.HostStatusContent{Connected: true},
+	))
+}
+
+// Stops waiting for the host. Returns false if the room was not waiting for its host.
+func (m *Manager) stopHostGrace() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.hostGraceTimer == nil {
+		return false
+	}
+	m.hostGraceTimer.Stop()
+	m.hostGraceTimer = nil
+	return true
+}
+
+// Ends the game when the host did not reconnect in time
+func (m *Manager) expireHost() {
+	m.mutex.Lock()
+	if m.HostClient != nil || m.hostGraceTimer == nil {
+		// The host made it back
+		m.mutex.Unlock()
+		return
+	}
+	m.hostGraceTimer = nil
+	m.mutex.Unlock()
+
+	log.Printf("Host of room %s did not reconnect, ending game", m.Code)
+	m.BroadcastMessage(models.CreateMessage(
+		models.MessageTypeLeave,
+		"Host",
+		models.MessageTextContent{Text: "has left the game"},
+	))
+	// Their readPumps are still running, so drop rather than unregister them
+	clients := append(m.playerClientList(), m.spectatorClientList()...)
+	for _, client := range clients {
+		m.queueDrop(client)
+	}
+	m.LiveGameStore.KillGame()
+	if len(clients) == 0 && m.onEmpty != nil {
+		log.Printf("Room %s is empty", m.Code)
+		m.onEmpty()
+	}
+}
+
+func (m *Manager) hasPlayerClient(playerId uuid.UUID) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for _, client := range m.PlayerClients {
+		if client.UserData.PlayerId == playerId {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) isEmpty() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.HostClient == nil && m.hostGraceTimer == nil && len(m.PlayerClients) == 0 && len(m.SpectatorClients) == 0
+}
+
+func (m *Manager) playerClientList() []*Client {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	clients := make([]*Client, 0, len(m.PlayerClients))
+	for _, client := range m.PlayerClients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+func (m *Manager) spectatorClientList() []*Client {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	clients := make([]*Client, 0, len(m.SpectatorClients))
+	for _, client := range m.SpectatorClients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// SpectatorClientCount returns the number of connected spectators
+func (m *Manager) SpectatorClientCount() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.SpectatorClients)
 }
 
 // PlayerClientCount returns the number of connected clients
@@ -191,12 +532,21 @@ func (m *Manager) AddClient(client *Client) error {
 	hasHost := m.HostClient != nil
 	m.mutex.RUnlock()
 	if client.UserData.IsHost {
-		if hasHost {
+		if hasHost && !client.UserData.Reconnected {
 			return errors.New("Game already has a host")
 		}
 		m.mutex.Lock()
 		defer m.mutex.Unlock()
+		if m.HostClient != nil {
+			// A reconnecting host replaces a connection that has not dropped yet
+			m.HostClient.Logf("Host replaced by a newer connection")
+			m.dropClientLocked(m.HostClient)
+		}
 		m.HostClient = client
+	} else if client.UserData.IsSpectator {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		m.SpectatorClients[client.ID] = client
 	} else {
 		// TODO: What if no host
 		// if !hasHost {
@@ -211,3 +561,14 @@ func (m *Manager) AddClient(client *Client) error {
 	}
 	return nil
 }
+
+// Caller must hold the mutex so the send channel cannot be closed during the send
+func trySend(client *Client, message models.Message) bool {
+	select {
+	case client.Send <- message:
+		return true
+	default:
+		client.Logf("Send buffer full, dropping message")
+		return false
+	}
+}
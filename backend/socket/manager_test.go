@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	livegame "github.com/adettinger/go-quizgame/liveGame"
 	"github.com/adettinger/go-quizgame/mocks"
 	"github.com/adettinger/go-quizgame/models"
 	"github.com/adettinger/go-quizgame/socket"
@@ -26,6 +27,7 @@ func createTestClient(t testing.TB, name string) *socket.Client {
 		Conn:    mockConn,
 		Manager: socket.NewManager(&webserver.QuestionStore{}),
 		Send:    make(chan models.Message, testClientBufferSize),
+		Closing: make(chan struct{}),
 		UserData: socket.UserData{
 			PlayerId: uuid.New(),
 			Name:     name,
@@ -139,6 +141,33 @@ func TestManager_SendToClient(t *testing.T) {
 	assert.False(t, success)
 }
 
+func TestManager_SendToPlayerAndHost(t *testing.T) {
+	manager := socket.NewManager(&webserver.QuestionStore{})
+
+	player := createTestClient(t, "testUser")
+	manager.PlayerClients[player.ID] = player
+	host := createTestClient(t, "Host")
+	host.UserData.IsHost = true
+	manager.HostClient = host
+
+	message := models.CreateMessage(
+		models.MessageTypeChat,
+		"System",
+		models.MessageTextContent{Text: "Test message"},
+	)
+
+	assert.True(t, manager.SendToPlayer(player.UserData.PlayerId, message))
+	assert.Equal(t, message, <-player.Send)
+
+	assert.True(t, manager.SendToHost(message))
+	assert.Equal(t, message, <-host.Send)
+
+	// Unknown player and missing host are reported
+	assert.False(t, manager.SendToPlayer(uuid.New(), message))
+	manager.HostClient = nil
+	assert.False(t, manager.SendToHost(message))
+}
+
 func TestManager_BroadcastMessage(t *testing.T) {
 	manager := socket.NewManager(&webserver.QuestionStore{})
 
@@ -226,20 +255,44 @@ func TestManager_Start_Register(t *testing.T) {
 	}
 }
 
+func TestManager_RegisterClient(t *testing.T) {
+	manager := socket.NewManager(&webserver.QuestionStore{})
+	go manager.Start()
+
+	client := createTestClient(t, "testUser")
+	assert.True(t, manager.RegisterClient(client))
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, manager.PlayerClientIDExists(client.ID))
+
+	// A stopped manager refuses clients instead of blocking forever
+	manager.Stop()
+	registered := make(chan bool)
+	go func() {
+		registered <- manager.RegisterClient(createTestClient(t, "testUser2"))
+	}()
+	select {
+	case ok := <-registered:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out registering with a stopped manager")
+	}
+}
+
 func TestManager_Start_Unregister(t *testing.T) {
 	const playerName = "testPlayer"
 	manager := socket.NewManager(&webserver.QuestionStore{})
+	manager.LiveGameStore.SetReconnectGracePeriod(100 * time.Millisecond)
 
 	// Create a client1
 	client1 := createTestClient(t, playerName)
 	client2 := createTestClient(t, "player2")
 
+	// Add player to the liveGameStore
+	client1.UserData.PlayerId, _ = manager.LiveGameStore.AddPlayer(playerName)
+	client2.UserData.PlayerId, _ = manager.LiveGameStore.AddPlayer("player2")
 	// Add the client directly to the manager
 	manager.PlayerClients[client1.ID] = client1
 	manager.PlayerClients[client2.ID] = client2
-	// Add player to the liveGameStore
-	manager.LiveGameStore.AddPlayer(playerName)
-	manager.LiveGameStore.AddPlayer("player2")
 
 	// Start the manager in a goroutine
 	go func() {
@@ -250,12 +303,21 @@ func TestManager_Start_Unregister(t *testing.T) {
 	manager.Unregister <- client1
 
 	// Give some time for processing
-	time.Sleep(100 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
 
 	// Verify the client was unregistered
 	assert.Equal(t, 1, manager.PlayerClientCount())
 	assert.False(t, manager.PlayerClientIDExists(client1.ID))
 
+	// Verify player keeps their place while they may reconnect
+	player, err := manager.LiveGameStore.GetPlayerByName(playerName)
+	assert.NoError(t, err)
+	assert.False(t, player.Connected)
+	assert.Len(t, client2.Send, 0)
+
+	// Give the grace period time to expire
+	time.Sleep(150 * time.Millisecond)
+
 	// Verify player was removed from gamestore
 	assert.False(t, manager.LiveGameStore.PlayerExistsByName(playerName))
 
@@ -273,6 +335,43 @@ func TestManager_Start_Unregister(t *testing.T) {
 	assert.False(t, isOpen, "Client send channel should be closed")
 }
 
+func TestManager_DisconnectOtherClients(t *testing.T) {
+	manager := socket.NewManager(&webserver.QuestionStore{})
+	playerId, _ := manager.LiveGameStore.AddPlayer("testPlayer")
+
+	staleClient := createTestClient(t, "testPlayer")
+	staleClient.UserData.PlayerId = playerId
+	newClient := createTestClient(t, "testPlayer")
+	newClient.UserData.PlayerId = playerId
+	manager.PlayerClients[staleClient.ID] = staleClient
+	manager.PlayerClients[newClient.ID] = newClient
+
+	go manager.Start()
+	defer manager.Stop()
+
+	manager.DisconnectOtherClients(playerId, newClient.ID)
+	time.Sleep(50 * time.Millisecond)
+
+	assert.False(t, manager.PlayerClientIDExists(staleClient.ID))
+	assert.True(t, manager.PlayerClientIDExists(newClient.ID))
+	_, isOpen := <-staleClient.Closing
+	assert.False(t, isOpen, "Stale connection should be told to close")
+
+	// The stale readPump may still be answering, so its send channel stays open until it unregisters
+	staleClient.SendError("late message")
+	assert.Len(t, staleClient.Send, 1)
+	manager.UnregisterClient(staleClient)
+	time.Sleep(50 * time.Millisecond)
+	<-staleClient.Send
+	_, isOpen = <-staleClient.Send
+	assert.False(t, isOpen, "Stale send channel should be closed once its readPump is done")
+
+	// The replaced connection does not disconnect the player
+	player, err := manager.LiveGameStore.GetPlayerById(playerId)
+	assert.NoError(t, err)
+	assert.True(t, player.Connected)
+}
+
 func TestManager_Start_Broadcast(t *testing.T) {
 	manager := socket.NewManager(&webserver.QuestionStore{})
 
@@ -356,3 +455,153 @@ func TestManager_Start_BroadcastFullBuffer(t *testing.T) {
 	// Verify the client was unregistered due to full buffer
 	assert.Equal(t, 0, manager.PlayerClientCount())
 }
+
+func TestManager_HostReconnect(t *testing.T) {
+	setup := func(t *testing.T) (*socket.Manager, *socket.Client, *socket.Client) {
+		manager := socket.NewManager(&webserver.QuestionStore{})
+		host := createTestClient(t, "Host")
+		host.UserData.IsHost = true
+		player := createTestClient(t, "testPlayer")
+		player.UserData.PlayerId, _ = manager.LiveGameStore.AddPlayer("testPlayer")
+		manager.HostClient = host
+		manager.PlayerClients[player.ID] = player
+		go manager.Start()
+		t.Cleanup(manager.Stop)
+		return manager, host, player
+	}
+
+	t.Run("Players are told the host is reconnecting", func(t *testing.T) {
+		manager, host, player := setup(t)
+
+		manager.UnregisterClient(host)
+		msg := receiveMessage(t, player)
+		assert.Equal(t, models.MessageTypeHostStatus, msg.Type)
+		content := msg.Content.(models.HostStatusContent)
+		assert.False(t, content.Connected)
+		assert.NotNil(t, content.ReconnectDeadline)
+		assert.True(t, manager.PlayerClientIDExists(player.ID))
+		assert.True(t, manager.LiveGameStore.PlayerExistsByName("testPlayer"))
+
+		newHost := createTestClient(t, "Host")
+		newHost.UserData.IsHost = true
+		newHost.UserData.Reconnected = true
+		manager.Register <- newHost
+
+		msg = receiveMessage(t, player)
+		assert.Equal(t, models.MessageTypeHostStatus, msg.Type)
+		assert.True(t, msg.Content.(models.HostStatusContent).Connected)
+		assert.True(t, manager.LiveGameStore.IsHostConnected())
+	})
+
+	t.Run("Game ends if the host does not reconnect in time", func(t *testing.T) {
+		manager, host, player := setup(t)
+		manager.SetHostReconnectGracePeriod(50 * time.Millisecond)
+
+		manager.UnregisterClient(host)
+		assert.Equal(t, models.MessageTypeHostStatus, receiveMessage(t, player).Type)
+
+		msg := receiveMessage(t, player)
+		assert.Equal(t, models.MessageTypeLeave, msg.Type)
+		assert.Equal(t, "Host", msg.PlayerName)
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, 0, manager.PlayerClientCount())
+		assert.False(t, manager.LiveGameStore.PlayerExistsByName("testPlayer"))
+		select {
+		case <-player.Closing:
+		default:
+			t.Error("Player connection should be closing")
+		}
+	})
+
+	t.Run("Reconnecting host replaces a stale connection", func(t *testing.T) {
+		manager, host, _ := setup(t)
+
+		newHost := createTestClient(t, "Host")
+		newHost.UserData.IsHost = true
+		newHost.UserData.Reconnected = true
+		manager.Register <- newHost
+		time.Sleep(50 * time.Millisecond)
+
+		select {
+		case <-host.Closing:
+		default:
+			t.Error("Old host connection should be closing")
+		}
+		// Its readPump is still running, so sending to it must not panic
+		host.SendError("Not allowed")
+		assert.Equal(t, models.MessageTypeError, receiveMessage(t, host).Type)
+
+		// The stale connection dropping later does not affect the new host
+		manager.UnregisterClient(host)
+		time.Sleep(50 * time.Millisecond)
+		_, isOpen := <-host.Send
+		assert.False(t, isOpen, "Old host send channel should be closed")
+		assert.True(t, manager.SendToHost(models.CreateMessage(models.MessageTypeChat, "System", nil)))
+	})
+}
+
+func receiveMessage(t *testing.T, client *socket.Client) models.Message {
+	t.Helper()
+	select {
+	case msg := <-client.Send:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for message")
+		return models.Message{}
+	}
+}
+
+func TestManager_Spectators(t *testing.T) {
+	manager := socket.NewManager(&webserver.QuestionStore{})
+	player := createTestClient(t, "testPlayer")
+	player.UserData.PlayerId, _ = manager.LiveGameStore.AddPlayer("testPlayer")
+	manager.PlayerClients[player.ID] = player
+	go manager.Start()
+	defer manager.Stop()
+
+	spectator := createTestClient(t, "Spectator")
+	spectator.UserData.IsSpectator = true
+	spectator.UserData.PlayerId = uuid.Nil
+	manager.Register <- spectator
+	time.Sleep(50 * time.Millisecond)
+
+	// Spectators are not players and do not announce themselves
+	assert.Equal(t, 1, manager.SpectatorClientCount())
+	assert.Equal(t, 1, manager.PlayerClientCount())
+	assert.Equal(t, []string{"testPlayer"}, manager.LiveGameStore.GetPlayerNameList())
+	assert.Len(t, player.Send, 0)
+
+	// Spectators receive broadcasts
+	manager.BroadcastMessage(models.CreateMessage(models.MessageTypeNextQuestion, "System", nil))
+	assert.Equal(t, models.MessageTypeNextQuestion, receiveMessage(t, spectator).Type)
+	assert.Equal(t, models.MessageTypeNextQuestion, receiveMessage(t, player).Type)
+
+	// and messages meant only for spectators
+	manager.SendToSpectators(models.CreateMessage(models.MessageTypeLeaderboard, "System", nil))
+	assert.Equal(t, models.MessageTypeLeaderboard, receiveMessage(t, spectator).Type)
+	assert.Len(t, player.Send, 0)
+
+	manager.UnregisterClient(spectator)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, manager.SpectatorClientCount())
+	assert.True(t, manager.LiveGameStore.PlayerExistsByName("testPlayer"))
+}
+
+func TestManager_RecordsBroadcasts(t *testing.T) {
+	manager := socket.NewManager(&webserver.QuestionStore{})
+	archive := livegame.NewArchive("")
+	manager.LiveGameStore.SetArchive(archive)
+	assert.NoError(t, manager.LiveGameStore.SetupGameOptions(livegame.GameOptions{TimeLimit: 30}))
+	go manager.Start()
+	defer manager.Stop()
+
+	manager.BroadcastMessage(models.CreateMessage(models.MessageTypeChat, "Alex", models.MessageTextContent{Text: "hi"}))
+	time.Sleep(50 * time.Millisecond)
+
+	events, err := archive.GetEvents(manager.LiveGameStore.GetGameId())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.GameEventBroadcast, events[0].Kind)
+	assert.Equal(t, models.MessageTypeChat, events[0].Message.Type)
+}
@@ -0,0 +1,84 @@
+package socket
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/adettinger/go-quizgame/utils"
+	"github.com/adettinger/go-quizgame/webserver"
+)
+
+const JoinCodeLength = 6
+
+// RoomRegistry holds one Manager per live game room, keyed by join code
+type RoomRegistry struct {
+	rooms             map[string]*Manager
+	questionStore     *webserver.QuestionStore
+	chatFilterOptions ChatFilterOptions // Each room gets its own chain built from these
+	mutex             sync.RWMutex
+}
+
+func NewRoomRegistry(qs *webserver.QuestionStore) *RoomRegistry {
+	return &RoomRegistry{
+		rooms:             make(map[string]*Manager),
+		questionStore:     qs,
+		chatFilterOptions: DefaultChatFilterOptions(),
+	}
+}
+
+// Creates a room with a new join code and starts its manager.
+// The room removes itself from the registry once it has no host and no players.
+func (rr *RoomRegistry) CreateRoom() *Manager {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	code := utils.GenerateJoinCode(JoinCodeLength)
+	for {
+		if _, exists := rr.rooms[code]; !exists {
+			break
+		}
+		code = utils.GenerateJoinCode(JoinCodeLength)
+	}
+	manager := NewManager(rr.questionStore)
+	manager.Code = code
+	manager.SetChatFilter(NewChatFilterChain(rr.chatFilterOptions))
+	manager.onEmpty = func() {
+		rr.RemoveRoom(code)
+	}
+	rr.rooms[code] = manager
+	go manager.Start()
+	return manager
+}
+
+// Sets how chat is filtered in rooms created from now on
+func (rr *RoomRegistry) SetChatFilterOptions(options ChatFilterOptions) {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+	rr.chatFilterOptions = options
+}
+
+// Gets a room by join code. Codes are not case sensitive.
+func (rr *RoomRegistry) GetRoom(code string) (*Manager, bool) {
+	rr.mutex.RLock()
+	defer rr.mutex.RUnlock()
+	manager, ok := rr.rooms[strings.ToUpper(code)]
+	return manager, ok
+}
+
+// Stops a room's manager and removes it from the registry
+func (rr *RoomRegistry) RemoveRoom(code string) {
+	rr.mutex.Lock()
+	manager, ok := rr.rooms[strings.ToUpper(code)]
+	delete(rr.rooms, strings.ToUpper(code))
+	rr.mutex.Unlock()
+
+	if ok {
+		manager.Stop()
+	}
+}
+
+func (rr *RoomRegistry) RoomCount() int {
+	rr.mutex.RLock()
+	defer rr.mutex.RUnlock()
+	return len(rr.rooms)
+}
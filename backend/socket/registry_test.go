@@ -0,0 +1,87 @@
+package socket_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adettinger/go-quizgame/socket"
+	"github.com/adettinger/go-quizgame/webserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoomRegistry_CreateAndGetRoom(t *testing.T) {
+	registry := socket.NewRoomRegistry(&webserver.QuestionStore{})
+
+	room1 := registry.CreateRoom()
+	room2 := registry.CreateRoom()
+	defer registry.RemoveRoom(room1.Code)
+	defer registry.RemoveRoom(room2.Code)
+
+	assert.Len(t, room1.Code, socket.JoinCodeLength)
+	assert.NotEqual(t, room1.Code, room2.Code)
+	assert.NotSame(t, room1.LiveGameStore, room2.LiveGameStore)
+	assert.Equal(t, 2, registry.RoomCount())
+
+	// Codes are not case sensitive
+	got, ok := registry.GetRoom(strings.ToLower(room1.Code))
+	assert.True(t, ok)
+	assert.Same(t, room1, got)
+
+	_, ok = registry.GetRoom("NOROOM")
+	assert.False(t, ok)
+}
+
+func TestRoomRegistry_RoomsAreIsolated(t *testing.T) {
+	registry := socket.NewRoomRegistry(&webserver.QuestionStore{})
+	room1 := registry.CreateRoom()
+	room2 := registry.CreateRoom()
+	defer registry.RemoveRoom(room1.Code)
+	defer registry.RemoveRoom(room2.Code)
+
+	client := createTestClient(t, "testUser")
+	client.Manager = room1
+	room1.Register <- client
+	time.Sleep(100 * time.Millisecond)
+
+	// The same name can join another room
+	_, err := room1.LiveGameStore.AddPlayer("Alex")
+	assert.NoError(t, err)
+	_, err = room2.LiveGameStore.AddPlayer("Alex")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, room1.PlayerClientCount())
+	assert.Equal(t, 0, room2.PlayerClientCount())
+}
+
+func TestRoomRegistry_RemovesEmptyRoom(t *testing.T) {
+	registry := socket.NewRoomRegistry(&webserver.QuestionStore{})
+	room := registry.CreateRoom()
+	room.SetHostReconnectGracePeriod(20 * time.Millisecond)
+
+	host := createTestClient(t, "Host")
+	host.UserData.IsHost = true
+	host.Manager = room
+	room.Register <- host
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, registry.RoomCount())
+
+	room.UnregisterClient(host)
+	time.Sleep(100 * time.Millisecond)
+
+	_, ok := registry.GetRoom(room.Code)
+	assert.False(t, ok)
+	assert.Equal(t, 0, registry.RoomCount())
+
+	// A stopped room drops messages instead of blocking
+	done := make(chan struct{})
+	go func() {
+		room.UnregisterClient(host)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Unregistering from a stopped room blocked")
+	}
+}
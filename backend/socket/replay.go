@@ -0,0 +1,37 @@
+package socket
+
+import (
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+)
+
+// Fastest speed a game can be replayed at
+const MaxReplaySpeed = 64
+
+// Sends the messages of an event log to send with the time between them divided by speed.
+// Host commands are only sent if includeCommands is set. Closes send once the log is done,
+// or as soon as done is closed.
+func Replay(events []models.GameEvent, speed float64, includeCommands bool, send chan<- models.Message, done <-chan struct{}) {
+	defer close(send)
+	var previous time.Time
+	for _, event := range events {
+		if event.Kind == models.GameEventHostCommand && !includeCommands {
+			continue
+		}
+		if !previous.IsZero() {
+			wait := time.Duration(float64(event.At.Sub(previous)) / speed)
+			select {
+			case <-time.After(wait):
+			case <-done:
+				return
+			}
+		}
+		previous = event.At
+		select {
+		case send <- event.Message:
+		case <-done:
+			return
+		}
+	}
+}
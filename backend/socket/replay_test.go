@@ -0,0 +1,64 @@
+package socket_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/socket"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestEvents() []models.GameEvent {
+	start := time.Now()
+	return []models.GameEvent{
+		{At: start, Kind: models.GameEventBroadcast, Message: models.CreateMessage(models.MessageTypeJoin, "Alex", nil)},
+		{At: start.Add(100 * time.Millisecond), Kind: models.GameEventHostCommand, Message: models.CreateMessage(models.MessageTypeStartGame, "Host", nil)},
+		{At: start.Add(200 * time.Millisecond), Kind: models.GameEventBroadcast, Message: models.CreateMessage(models.MessageTypeNextQuestion, "System", nil)},
+	}
+}
+
+func collectReplay(events []models.GameEvent, speed float64, includeCommands bool) ([]models.MessageType, time.Duration) {
+	send := make(chan models.Message)
+	started := time.Now()
+	go socket.Replay(events, speed, includeCommands, send, make(chan struct{}))
+	var types []models.MessageType
+	for message := range send {
+		types = append(types, message.Type)
+	}
+	return types, time.Since(started)
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("Plays at the speed it happened", func(t *testing.T) {
+		types, elapsed := collectReplay(createTestEvents(), 1, false)
+		assert.Equal(t, []models.MessageType{models.MessageTypeJoin, models.MessageTypeNextQuestion}, types)
+		assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+	})
+
+	t.Run("Plays faster", func(t *testing.T) {
+		types, elapsed := collectReplay(createTestEvents(), 4, true)
+		assert.Equal(t, []models.MessageType{models.MessageTypeJoin, models.MessageTypeStartGame, models.MessageTypeNextQuestion}, types)
+		assert.GreaterOrEqual(t, elapsed, 50*time.Millisecond)
+		assert.Less(t, elapsed, 200*time.Millisecond)
+	})
+
+	t.Run("Stops when the viewer leaves", func(t *testing.T) {
+		send := make(chan models.Message, 10)
+		done := make(chan struct{})
+		finished := make(chan struct{})
+		go func() {
+			socket.Replay(createTestEvents(), 1, false, send, done)
+			close(finished)
+		}()
+		assert.Equal(t, models.MessageTypeJoin, (<-send).Type)
+		close(done)
+		select {
+		case <-finished:
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Replay kept going after the viewer left")
+		}
+		_, ok := <-send
+		assert.False(t, ok)
+	})
+}
@@ -2,6 +2,8 @@ package types
 
 import (
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -53,3 +55,162 @@ type ErrDuplicatePlayerName struct {
 func (e *ErrDuplicatePlayerName) Error() string {
 	return fmt.Sprintf("Duplicate player name: %v", e.PlayerName)
 }
+
+type ErrPlayerNotFound struct {
+	PlayerId uuid.UUID
+}
+
+func (e *ErrPlayerNotFound) Error() string {
+	return fmt.Sprintf("Player not found: %v", e.PlayerId.String())
+}
+
+type ErrQuestionNotOpen struct {
+	QuestionNumber int
+}
+
+func (e *ErrQuestionNotOpen) Error() string {
+	return fmt.Sprintf("Question %d is not accepting answers", e.QuestionNumber)
+}
+
+type ErrAnswerTooLate struct {
+	QuestionNumber int
+	Deadline       time.Time
+}
+
+func (e *ErrAnswerTooLate) Error() string {
+	return fmt.Sprintf("Time is up for question %d", e.QuestionNumber)
+}
+
+type ErrInvalidAnswer struct {
+	QuestionNumber int
+	Reason         string
+}
+
+func (e *ErrInvalidAnswer) Error() string {
+	return fmt.Sprintf("Answer to question %d is invalid. %v", e.QuestionNumber, e.Reason)
+}
+
+type ErrAnswerAlreadySubmitted struct {
+	PlayerId       uuid.UUID
+	QuestionNumber int
+}
+
+func (e *ErrAnswerAlreadySubmitted) Error() string {
+	return fmt.Sprintf("Answer already submitted for question %d", e.QuestionNumber)
+}
+
+type ErrInvalidReconnectToken struct {
+	PlayerName string
+}
+
+func (e *ErrInvalidReconnectToken) Error() string {
+	return fmt.Sprintf("Invalid reconnect token for player: %v", e.PlayerName)
+}
+
+type ErrGamePaused struct{}
+
+func (e *ErrGamePaused) Error() string {
+	return "Game is paused"
+}
+
+type ErrInvalidHostToken struct{}
+
+func (e *ErrInvalidHostToken) Error() string {
+	return "Invalid host token"
+}
+
+type ErrPlayerNameNotFound struct {
+	PlayerName string
+}
+
+func (e *ErrPlayerNameNotFound) Error() string {
+	return fmt.Sprintf("Player not found: %v", e.PlayerName)
+}
+
+type ErrPlayerBanned struct {
+	PlayerName string
+}
+
+func (e *ErrPlayerBanned) Error() string {
+	return fmt.Sprintf("Player is banned from this game: %v", e.PlayerName)
+}
+
+type ErrTeamNotFound struct {
+	Team string
+}
+
+func (e *ErrTeamNotFound) Error() string {
+	return fmt.Sprintf("Team not found: %v", e.Team)
+}
+
+type ErrPlayerEliminated struct {
+	PlayerId uuid.UUID
+}
+
+func (e *ErrPlayerEliminated) Error() string {
+	return "Eliminated players cannot answer"
+}
+
+type ErrLobbyLocked struct{}
+
+func (e *ErrLobbyLocked) Error() string {
+	return "The lobby is locked"
+}
+
+type ErrLobbyFull struct {
+	MaxPlayers int
+}
+
+func (e *ErrLobbyFull) Error() string {
+	return fmt.Sprintf("The game is full: %d players", e.MaxPlayers)
+}
+
+// Returned when a player tries to join a game that has already started
+type ErrGameInProgress struct {
+	CanSpectate bool // The host lets late arrivals watch instead
+}
+
+func (e *ErrGameInProgress) Error() string {
+	if e.CanSpectate {
+		return "The game has already started. Join as a spectator instead"
+	}
+	return "The game has already started"
+}
+
+type ErrTranscriptNotFound struct {
+	GameId uuid.UUID
+}
+
+func (e *ErrTranscriptNotFound) Error() string {
+	return fmt.Sprintf("No results for game: %v", e.GameId.String())
+}
+
+type ErrEventLogNotFound struct {
+	GameId uuid.UUID
+}
+
+func (e *ErrEventLogNotFound) Error() string {
+	return fmt.Sprintf("No event log for game: %v", e.GameId.String())
+}
+
+type ErrChatTooLong struct {
+	MaxLength int
+}
+
+func (e *ErrChatTooLong) Error() string {
+	return fmt.Sprintf("Chat messages can be at most %d characters", e.MaxLength)
+}
+
+type ErrChatRateLimited struct {
+	RetryAfter time.Duration
+}
+
+func (e *ErrChatRateLimited) Error() string {
+	return fmt.Sprintf("You are sending messages too quickly. Try again in %d seconds", max(int(math.Ceil(e.RetryAfter.Seconds())), 1))
+}
+
+type ErrChatDuplicate struct{}
+
+func (e *ErrChatDuplicate) Error() string {
+	return "You already sent that message"
+}
@@ -1,10 +1,15 @@
 package utils
 
 import (
+	"crypto/rand"
+	"math/big"
 	"regexp"
 	"strings"
 )
 
+// Letters and digits that are hard to confuse when read aloud or off a projector
+const joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
 func CleanInput(input string) string {
 	return strings.ToLower(strings.TrimSpace(input))
 }
@@ -23,3 +28,16 @@ func IsPlayerNameValid(name string, maxLength int) bool {
 		IsAlphanumeric(spacesRemovedName) && //has only alphanumeric and spaces
 		len(name) <= maxLength) //Fits in max length
 }
+
+func GenerateJoinCode(length int) string {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(joinCodeAlphabet)))
+	for range length {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		sb.WriteByte(joinCodeAlphabet[n.Int64()])
+	}
+	return sb.String()
+}
@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/adettinger/go-quizgame/testutils"
@@ -62,3 +63,10 @@ func TestIsPlayerNameValid(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateJoinCode(t *testing.T) {
+	code := utils.GenerateJoinCode(6)
+	testutils.AssertEqual(t, len(code), 6)
+	testutils.AssertTrue(t, utils.IsAlphanumeric(code))
+	testutils.AssertEqual(t, code, strings.ToUpper(code))
+}
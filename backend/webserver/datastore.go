@@ -2,6 +2,8 @@ package webserver
 
 import (
 	"errors"
+	"math/rand/v2"
+	"slices"
 	"sync"
 
 	"github.com/adettinger/go-quizgame/csv"
@@ -85,6 +87,9 @@ func (ds *QuestionStore) AddProblem(pr models.CreateProblemRequest) (models.Prob
 	if err = models.ValidateChoices(problemType, pr.Choices, pr.Answer); err != nil {
 		return models.Problem{}, err
 	}
+	if err = models.ValidateAliases(problemType, pr.Answer, pr.Aliases); err != nil {
+		return models.Problem{}, err
+	}
 	if pr.Question == "" {
 		return models.Problem{}, errors.New("Question cannot be empty string")
 	}
@@ -97,7 +102,8 @@ func (ds *QuestionStore) AddProblem(pr models.CreateProblemRequest) (models.Prob
 		Type:     problemType,
 		Question: pr.Question,
 		Choices:  pr.Choices,
-		Answer:   pr.Answer,
+		Answer:   models.CanonicalAnswer(problemType, pr.Answer),
+		Aliases:  pr.Aliases,
 	}
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
@@ -115,6 +121,9 @@ func (ds *QuestionStore) EditProblem(pr models.EditProblemRequest) error {
 	if err = models.ValidateChoices(problemType, pr.Choices, pr.Answer); err != nil {
 		return err
 	}
+	if err = models.ValidateAliases(problemType, pr.Answer, pr.Aliases); err != nil {
+		return err
+	}
 	if pr.Question == "" {
 		return errors.New("Question cannot be empty string")
 	}
@@ -127,7 +136,8 @@ func (ds *QuestionStore) EditProblem(pr models.EditProblemRequest) error {
 		Type:     problemType,
 		Question: pr.Question,
 		Choices:  pr.Choices,
-		Answer:   pr.Answer,
+		Answer:   models.CanonicalAnswer(problemType, pr.Answer),
+		Aliases:  pr.Aliases,
 	}
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
@@ -158,11 +168,20 @@ func (ds *QuestionStore) GetQuestions() []models.Question {
 	questions := make([]models.Question, len(ds.problems))
 	index := 0
 	for _, p := range ds.problems {
+		choices := p.DisplayChoices()
+		if p.Type == models.ProblemTypeOrdering {
+			// Often written in the correct order, which would give the answer away
+			choices = slices.Clone(choices)
+			rand.Shuffle(len(choices), func(i, j int) {
+				choices[i], choices[j] = choices[j], choices[i]
+			})
+		}
 		questions[index] = models.Question{
 			Id:       p.Id,
 			Type:     p.Type,
 			Question: p.Question,
-			Choices:  p.Choices,
+			Choices:  choices,
+			Targets:  p.MatchTargets(),
 		}
 		index++
 	}
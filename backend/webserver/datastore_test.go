@@ -1,6 +1,7 @@
 package webserver
 
 import (
+	"slices"
 	"sync"
 	"testing"
 
@@ -14,15 +15,11 @@ var problems = map[uuid.UUID]models.Problem{
 	uuid.MustParse("60d1584a-9d09-4e2d-be5c-1150fafa454f"): {Id: uuid.MustParse("60d1584a-9d09-4e2d-be5c-1150fafa454f"), Type: "text", Question: "2*2", Choices: []string{}, Answer: "4"},
 }
 
-var uuids []uuid.UUID = func() []uuid.UUID {
-	toReturn := make([]uuid.UUID, len(problems))
-	i := 0
-	for k, _ := range problems {
-		toReturn[i] = k
-		i++
-	}
-	return toReturn
-}()
+// Listed explicitly so tests do not depend on map iteration order
+var uuids []uuid.UUID = []uuid.UUID{
+	uuid.MustParse("c620af48-3af0-4216-a229-65c539a00202"),
+	uuid.MustParse("60d1584a-9d09-4e2d-be5c-1150fafa454f"),
+}
 
 var ds = QuestionStore{
 	fileName: "test",
@@ -57,6 +54,30 @@ func TestGetProblemByID(t *testing.T) {
 	})
 }
 
+func TestGetQuestions(t *testing.T) {
+	t.Run("Ordering choices are shuffled", func(t *testing.T) {
+		ordering := models.Problem{
+			Id:       uuid.New(),
+			Type:     models.ProblemTypeOrdering,
+			Question: "Order these ages",
+			Choices:  []string{"Stone", "Bronze", "Iron", "Steel"},
+			Answer:   `["Stone","Bronze","Iron","Steel"]`,
+		}
+		store, err := NewDataStoreFromData([]models.Problem{ordering})
+		AssertNoError(t, err)
+
+		shuffled := false
+		for range 20 {
+			choices := store.GetQuestions()[0].Choices
+			AssertEquals(t, slices.Equal(slices.Sorted(slices.Values(choices)), slices.Sorted(slices.Values(ordering.Choices))), true)
+			shuffled = shuffled || !slices.Equal(choices, ordering.Choices)
+		}
+		AssertEquals(t, shuffled, true)
+		// The stored problem keeps its order
+		AssertEquals(t, slices.Equal(store.problems[ordering.Id].Choices, []string{"Stone", "Bronze", "Iron", "Steel"}), true)
+	})
+}
+
 func AssertNoError(t testing.TB, got error) {
 	t.Helper()
 	if got != nil {
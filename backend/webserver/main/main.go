@@ -3,9 +3,12 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/adettinger/go-quizgame/controllers"
+	livegame "github.com/adettinger/go-quizgame/liveGame"
+	"github.com/adettinger/go-quizgame/socket"
 	"github.com/adettinger/go-quizgame/webserver"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -22,10 +25,33 @@ func main() {
 	problemController := controllers.NewProblemController(ds)
 	quizController := controllers.NewQuizController(ds)
 	wsController := controllers.NewWebSocketController(ds)
+	// Keep a copy of each game's results on disk if a directory is given
+	if resultsDir := os.Getenv("QUIZGAME_RESULTS_DIR"); resultsDir != "" {
+		wsController.SetArchive(livegame.NewArchive(resultsDir))
+	}
 
-	go wsController.GetManager().Start()
+	// Mask the words in this file in chat instead of the default list
+	if wordListPath := os.Getenv("QUIZGAME_CHAT_WORDLIST"); wordListPath != "" {
+		words, err := socket.ReadWordList(wordListPath)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		options := socket.DefaultChatFilterOptions()
+		options.ProfanityWords = words
+		wsController.SetChatFilterOptions(options)
+	}
 
 	router := gin.Default()
+	// Bans by address rely on the client IP, so only believe forwarding headers from known proxies
+	var trustedProxies []string
+	if proxies := os.Getenv("QUIZGAME_TRUSTED_PROXIES"); proxies != "" {
+		trustedProxies = strings.Split(proxies, ",")
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
@@ -53,8 +79,11 @@ func main() {
 	router.GET("/quiz/start", quizController.StartQuiz)
 	router.POST("/quiz/submit", quizController.SubmitQuiz)
 
-	router.GET("/liveGame/player/:playerName", wsController.HandlePlayerConnection)
+	router.GET("/liveGame/:code/player/:playerName", wsController.HandlePlayerConnection)
 	router.GET("/liveGame/host", wsController.HandleHostConnection)
+	router.GET("/liveGame/:code/spectator", wsController.HandleSpectatorConnection)
+	router.GET("/liveGame/results/:gameId", wsController.GetResults)
+	router.GET("/liveGame/replay/:gameId", wsController.HandleReplay)
 
 	router.Run("localhost:8080")
 }
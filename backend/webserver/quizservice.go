@@ -1,7 +1,6 @@
 package webserver
 
 import (
-	"strings"
 	"time"
 
 	"github.com/adettinger/go-quizgame/models"
@@ -21,7 +20,7 @@ func NewQuizService(ds *QuestionStore, ss *SessionStore) *QuizService {
 	}
 }
 
-func (qs *QuizService) EvaluateQuiz(sessionId uuid.UUID, submission []models.QuestionSubmission) (models.EvaluateQuizResponse, error) {
+func (qs *QuizService) EvaluateQuiz(sessionId uuid.UUID, submission []models.QuestionSubmission, creditMode models.CreditMode) (models.EvaluateQuizResponse, error) {
 	isActive, err := qs.ss.IsSessionActive(sessionId, time.Now())
 	if err != nil {
 		return models.EvaluateQuizResponse{}, &types.ErrSessionNotFound{SessionID: sessionId}
@@ -32,21 +31,20 @@ func (qs *QuizService) EvaluateQuiz(sessionId uuid.UUID, submission []models.Que
 	}
 
 	questionResponses := make([]models.QuestionResponse, len(submission))
-	score := 0
+	score := 0.0
 	for i, s := range submission {
 		matchingProblem, err := qs.ds.GetProblemById(s.QuestionId)
 		if err != nil {
 			return models.EvaluateQuizResponse{}, &types.ErrProblemNotFound{ProblemId: s.QuestionId}
 		}
-		correct := strings.EqualFold(s.Answer, matchingProblem.Answer)
+		credit := matchingProblem.Grade(s.Answer, creditMode)
 		questionResponses[i] = models.QuestionResponse{
 			Id:      s.QuestionId,
 			Answer:  matchingProblem.Answer,
-			Correct: correct,
-		}
-		if correct {
-			score++
+			Correct: credit == 1,
+			Score:   credit,
 		}
+		score += credit
 	}
 
 	return models.EvaluateQuizResponse{
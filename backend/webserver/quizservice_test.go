@@ -0,0 +1,86 @@
+package webserver_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adettinger/go-quizgame/models"
+	"github.com/adettinger/go-quizgame/types"
+	"github.com/adettinger/go-quizgame/webserver"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var multiProblem = models.Problem{
+	Id:       uuid.MustParse("5a3c2e0f-51f4-4f7e-9a38-2b2f0c7d9e41"),
+	Type:     models.ProblemTypeMulti,
+	Question: "Primary colours",
+	Choices:  []string{"Red", "Green", "Blue", "Yellow"},
+	Answer:   `["red","blue","yellow"]`,
+}
+
+var trueFalseProblem = models.Problem{
+	Id:       uuid.MustParse("c4e7a1d2-6b3f-4e8a-9f0c-1d2e3f4a5b6c"),
+	Type:     models.ProblemTypeTrueFalse,
+	Question: "The sun is a star",
+	Answer:   "true",
+}
+
+func TestEvaluateQuiz(t *testing.T) {
+	ds, err := webserver.NewDataStoreFromData(append([]models.Problem{multiProblem}, problemSet...))
+	require.NoError(t, err)
+	ss := webserver.NewSessionStore()
+	service := webserver.NewQuizService(ds, ss)
+	submission := []models.QuestionSubmission{
+		{QuestionId: multiProblem.Id, Answer: `["Red","Blue"]`},
+		{QuestionId: problemSet[0].Id, Answer: "3"},
+	}
+
+	t.Run("All or nothing", func(t *testing.T) {
+		sessionId, _ := ss.CreateSession(time.Minute)
+		response, err := service.EvaluateQuiz(sessionId, submission, models.CreditModeAllOrNothing)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, response.Score)
+		assert.Equal(t, models.QuestionResponse{Id: multiProblem.Id, Answer: multiProblem.Answer}, response.Answers[0])
+		assert.Equal(t, models.QuestionResponse{Id: problemSet[0].Id, Answer: "3", Correct: true, Score: 1}, response.Answers[1])
+	})
+
+	t.Run("Proportional", func(t *testing.T) {
+		sessionId, _ := ss.CreateSession(time.Minute)
+		response, err := service.EvaluateQuiz(sessionId, submission, models.CreditModeProportional)
+		require.NoError(t, err)
+		assert.InDelta(t, 5.0/3, response.Score, 1e-9)
+		assert.False(t, response.Answers[0].Correct)
+		assert.InDelta(t, 2.0/3, response.Answers[0].Score, 1e-9)
+	})
+
+	t.Run("Unknown session", func(t *testing.T) {
+		_, err := service.EvaluateQuiz(uuid.New(), submission, models.CreditModeAllOrNothing)
+		var notFoundErr *types.ErrSessionNotFound
+		assert.True(t, errors.As(err, &notFoundErr))
+	})
+	t.Run("Aliases are accepted but the answer is returned", func(t *testing.T) {
+		problem := models.Problem{Id: uuid.New(), Type: models.ProblemTypeText, Question: "Largest economy", Answer: "usa", Aliases: []string{"united states", "us"}}
+		ds, err := webserver.NewDataStoreFromData([]models.Problem{problem})
+		require.NoError(t, err)
+		service := webserver.NewQuizService(ds, ss)
+		sessionId, _ := ss.CreateSession(time.Minute)
+		response, err := service.EvaluateQuiz(sessionId, []models.QuestionSubmission{{QuestionId: problem.Id, Answer: "United States"}}, models.CreditModeAllOrNothing)
+		require.NoError(t, err)
+		assert.Equal(t, models.QuestionResponse{Id: problem.Id, Answer: "usa", Correct: true, Score: 1}, response.Answers[0])
+	})
+
+	t.Run("True/false accepts yes and no", func(t *testing.T) {
+		ds, err := webserver.NewDataStoreFromData([]models.Problem{trueFalseProblem})
+		require.NoError(t, err)
+		service := webserver.NewQuizService(ds, ss)
+		for answer, want := range map[string]float64{"Yes": 1, "oui": 1, "no": 0, "maybe": 0} {
+			sessionId, _ := ss.CreateSession(time.Minute)
+			response, err := service.EvaluateQuiz(sessionId, []models.QuestionSubmission{{QuestionId: trueFalseProblem.Id, Answer: answer}}, models.CreditModeAllOrNothing)
+			require.NoError(t, err)
+			assert.Equal(t, want, response.Score, answer)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/adettinger/go-quizgame/controllers"
@@ -218,12 +219,46 @@ func TestAddProblem(t *testing.T) {
 		{
 			"Create problem",
 			map[string]interface{}{
+				"Type":     "text",
 				"Question": "Test question",
 				"Answer":   "Test answer",
 			},
 			true,
 			http.StatusCreated,
 		},
+		{
+			"Create true/false problem without choices",
+			map[string]interface{}{
+				"Type":     "truefalse",
+				"Question": "The sun is a star",
+				"Answer":   "true",
+			},
+			true,
+			http.StatusCreated,
+		},
+		{
+			"Create text problem with aliases",
+			map[string]interface{}{
+				"Type":     "text",
+				"Question": "Largest economy",
+				"Answer":   "USA",
+				"Aliases":  []string{"United States", "US"},
+			},
+			true,
+			http.StatusCreated,
+		},
+		{
+			"Choice problem cannot have aliases",
+			map[string]interface{}{
+				"Type":     "choice",
+				"Question": "Largest economy",
+				"Choices":  []string{"USA", "China"},
+				"Answer":   "USA",
+				"Aliases":  []string{"US"},
+			},
+			false,
+			http.StatusBadRequest,
+		},
 		{
 			"Invalid request",
 			map[string]interface{}{},
@@ -278,8 +313,9 @@ func areProblemSlicesEqual(a []models.Problem, b []models.Problem) bool {
 	if len(a) != len(b) {
 		return false
 	}
-	for i, _ := range a {
-		if !a[i].Equal(b[i]) {
+	// The store is backed by a map, so problems may come back in any order
+	for _, problem := range a {
+		if !slices.ContainsFunc(b, problem.Equal) {
 			return false
 		}
 	}
@@ -15,6 +15,7 @@ export interface workingProblem {
     Question: string;
     Choices: string[];
     Answer: string;
+    Aliases?: string[];
 }
 
 export function CreateProblemForm() {
@@ -55,7 +56,7 @@ export function CreateProblemForm() {
             Question: formValues.Question.trim(),
             Answer: formValues.Answer.trim(),
             // TODO: Clean all the choices
-            Choices: formValues.Type === ProblemType.Text ? [] : formValues.Choices,
+            Choices: formValues.Type === ProblemType.Choice ? formValues.Choices : [],
         })
     }
 
@@ -65,7 +65,9 @@ export function EditProblem() {
             Question: formValues.Question.trim(),
             Answer: formValues.Answer.trim(),
             // TODO: Clean all the choices
-            Choices: formValues.Type === ProblemType.Text ? [] : formValues.Choices,
+            Choices: formValues.Type === ProblemType.Choice ? formValues.Choices : [],
+            // Aliases cannot be edited here yet, so a text problem keeps the ones it has
+            Aliases: formValues.Type === ProblemType.Text ? formValues.Aliases : [],
         })
     }
 
@@ -182,7 +182,7 @@ export function Game() {
                                     <Form.Label>{problem.Question}</Form.Label>
                                     <div>
                                         {
-                                            problem.Type === ProblemType.Text &&
+                                            (problem.Type === ProblemType.Text || problem.Type === ProblemType.Numeric) &&
                                             <TextField.Root
                                                 required
                                                 value={problem.Guess}
@@ -192,7 +192,7 @@ export function Game() {
                                                 <TextField.Slot />
                                             </TextField.Root>
                                         }
-                                        {problem.Type === ProblemType.Choice &&
+                                        {(problem.Type === ProblemType.Choice || problem.Type === ProblemType.TrueFalse) &&
                                             <DropdownMenu.Root>
                                                 <DropdownMenu.Trigger>
                                                     <Button color='gray' variant='soft'>{problem.Guess === "" ? "Select an option" : problem.Guess}<DropdownMenu.TriggerIcon /></Button>
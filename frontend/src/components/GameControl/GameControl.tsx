@@ -117,14 +117,14 @@ export function GameControl() {
         }
     }, [playerList])
 
-    const connectWebSocket = async (name: string) => {
+    const connectWebSocket = async (name: string, joinCode: string) => {
         if (isSocketConnected()) {
             addMessage(createTextMessage(messageType.Admin, "Aleady connected!"));
             return;
         }
 
         try {
-            const wsUrl = `ws://localhost:8080/liveGame/player/${name.trim()}`;
+            const wsUrl = `ws://localhost:8080/liveGame/${encodeURIComponent(joinCode.trim())}/player/${name.trim()}`;
             const httpUrl = wsUrl.replace('ws:', 'http:');
 
             const response = await fetch(httpUrl, {
@@ -143,6 +143,9 @@ export function GameControl() {
                 if (statusCode === 400) {
                     setServerError('Invalid player name format');
                     errorMessage = "Invalid player name format (400 Bad Request)";
+                } else if (statusCode === 404) {
+                    setServerError('Game not found');
+                    errorMessage = "No game with that join code (404 Not Found)";
                 } else if (statusCode === 409) {
                     setServerError('Player name already taken');
                     errorMessage = "Player name already taken (409 Conflict)";
@@ -208,7 +211,7 @@ export function GameControl() {
 
             <PlayerNameForm
                 isConnected={isSocketConnected()}
-                onSubmit={(name: string) => { connectWebSocket(name) }}
+                onSubmit={(name: string, joinCode: string) => { connectWebSocket(name, joinCode) }}
                 onQuit={disconnectWebSocket}
             />
 
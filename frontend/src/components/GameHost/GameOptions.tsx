@@ -21,6 +21,7 @@ export function GameOptions() {
     const [chatMessages, setChatMessages] = useState<chatMessage[]>([]);
     const [gameStatus, setGameStatus] = useState<GameStatus>(GameStatus.NotStarted);
     const [questionStatus, setQuestionStatus] = useState<QuestionStatus>(QuestionStatus.NotStarted);
+    const [joinCode, setJoinCode] = useState('');
 
 
 
@@ -112,6 +113,11 @@ export function GameOptions() {
                     setPlayerList(createPlayers(msg.content?.Names));
                 }
                 break;
+            case messageType.RoomCreated:
+                if ('code' in msg.content) {
+                    setJoinCode(msg.content.code);
+                }
+                break;
             case messageType.Error:
                 if ('Text' in msg.content) {
                     setServerError(msg.content.Text);
@@ -183,6 +189,7 @@ export function GameOptions() {
 
             socketRef.current.onclose = () => {
                 setConnectionStatus(ConnectionStatus.Disconnected);
+                setJoinCode('');
                 setChatMessages([]);
                 setPlayerList([]); //Not necessary because will be overwritten if rejoin, but render looks cleaner
                 addMessage(createTextMessage(messageType.Admin, "Connection closed"));
@@ -236,6 +243,9 @@ export function GameOptions() {
             {
                 isSocketConnected() ?
                     <>
+                        {joinCode !== '' &&
+                            <Text size="6" weight="bold">Join code: {joinCode}</Text>
+                        }
                         <PlayerBadgeList players={playerList} />
                         <ChatWindow onMessageSend={sendChatMessage} messages={chatMessages} />
                     </>
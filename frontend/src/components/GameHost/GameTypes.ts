@@ -9,6 +9,7 @@ export enum messageType {
     PlayerList = "player_list",
     StartGame = "start",
     ReceiveingQuestion = "question",
+    RoomCreated = "room_created",
 }
 
 export interface MessageTextContent {
@@ -19,12 +20,16 @@ export interface MessagePlayerListContent {
     Names: string[];
 }
 
+export interface MessageRoomContent {
+    code: string;
+}
+
 
 export interface WebSocketMessage {
     type: messageType;
     timestamp: Date;
     playerName: string;
-    content: MessageTextContent | MessagePlayerListContent; //Set to union of possible message content types that are defined in backend
+    content: MessageTextContent | MessagePlayerListContent | MessageRoomContent; //Set to union of possible message content types that are defined in backend
 }
 
 export enum ConnectionStatus {
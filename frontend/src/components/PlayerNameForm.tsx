@@ -4,12 +4,13 @@ import { useState } from "react";
 
 export interface PlayerNameFormProps {
     isConnected: boolean;
-    onSubmit: (name: string) => void;
+    onSubmit: (name: string, joinCode: string) => void;
     onQuit: () => void;
 }
 
 export function PlayerNameForm({ isConnected, onSubmit, onQuit }: PlayerNameFormProps) {
     const [playerName, setPlayerName] = useState('');
+    const [joinCode, setJoinCode] = useState('');
 
     const MaxNameLength = 20;
 
@@ -20,16 +21,28 @@ export function PlayerNameForm({ isConnected, onSubmit, onQuit }: PlayerNameForm
         return true;
     };
 
+    const isJoinCodeValid = (code: string) => {
+        return code.trim() !== '';
+    };
+
     return (
         <Flex direction="row" gap="3">
+            <TextField.Root
+                value={joinCode}
+                onChange={(event) => { setJoinCode(event.target.value.replace(/[^a-zA-Z0-9]/g, '').toUpperCase()) }}
+                placeholder="Join code"
+                disabled={isConnected}
+            >
+                <TextField.Slot />
+            </TextField.Root>
             <TextField.Root
                 value={playerName}
                 onChange={(event) => { setPlayerName(event.target.value.replace(/[^a-zA-Z0-9\s]/g, '')) }}
                 placeholder="Enter player name"
                 disabled={isConnected}
                 onKeyDown={(event) => {
-                    if (event.key === 'Enter' && playerName.trim() !== '') {
-                        onSubmit(playerName);
+                    if (event.key === 'Enter' && playerName.trim() !== '' && isJoinCodeValid(joinCode)) {
+                        onSubmit(playerName, joinCode);
                     }
                 }}
             >
@@ -43,18 +56,19 @@ export function PlayerNameForm({ isConnected, onSubmit, onQuit }: PlayerNameForm
                 </Button>
                 :
                 <Tooltip content={
-                    isNameValid(playerName)
+                    isNameValid(playerName) && isJoinCodeValid(joinCode)
                         ? "Click to join the game"
                         :
                         <Flex direction="column">
                             <Text>Player name must contain only letters, numbers, and spaces, and cannot be empty</Text>
                             <Text>{`Cannot be longer than ${MaxNameLength} characters`}</Text>
                             <Text>Cannot begin or end with space</Text>
+                            <Text>Join code is required</Text>
                         </Flex>
                 }>
                     <Button
-                        onClick={() => onSubmit(playerName)}
-                        disabled={!isNameValid(playerName)}
+                        onClick={() => onSubmit(playerName, joinCode)}
+                        disabled={!isNameValid(playerName) || !isJoinCodeValid(joinCode)}
                     >
                         Join Game
                     </Button>
@@ -110,7 +110,7 @@ export function ProblemForm({ formValues, setFormValues, onSubmit, submitDisable
 
                 <Form.Submit asChild >
                     <Tooltip content={
-                        formValues.Type === ProblemType.Text
+                        formValues.Type !== ProblemType.Choice
                             ? "Fill in all fields"
                             :
                             <Flex direction="column">
@@ -75,6 +75,7 @@ interface EditProblemFormData {
     Question: string;
     Answer: string;
     Choices: string[];
+    Aliases?: string[];
 }
 
 export async function editProblem(data: EditProblemFormData) {
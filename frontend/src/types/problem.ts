@@ -4,11 +4,14 @@ export interface Problem {
     Question: string;
     Choices: string[];
     Answer: string;
+    Aliases?: string[]; // Other accepted answers to a text problem
 }
 
 export enum ProblemType {
     Text = "text",
     Choice = "choice",
+    Numeric = "numeric",
+    TrueFalse = "truefalse",
 }
 
 export function getEnumKeyByValue<T extends { [index: string]: string }>(enumObj: T, value: string): keyof T | undefined {